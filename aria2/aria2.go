@@ -4,14 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"net/url"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 )
 
@@ -24,13 +29,20 @@ type DownloadStatus struct {
 	DownloadSpeed   string `json:"downloadSpeed"`   // 下载速度
 	PieceLength     string `json:"pieceLength"`     // 分片大小
 	NumPieces       string `json:"numPieces"`       // 分片数量
+	Bitfield        string `json:"bitfield"`        // 分片完成情况（十六进制位图）
 	Connections     string `json:"connections"`     // 连接数
 	ErrorCode       string `json:"errorCode"`       // 错误代码
 	ErrorMessage    string `json:"errorMessage"`    // 错误信息
 	Files           []File `json:"files"`           // 文件列表
+	InfoHash        string `json:"infoHash"`        // BitTorrent 任务的 info hash，其他任务为空
+	Dir             string `json:"dir"`             // 下载目录
+
+	VerifiedLength         string `json:"verifiedLength"`         // 校验中已校验的大小，只在校验期间存在
+	VerifyIntegrityPending string `json:"verifyIntegrityPending"` // 等待校验时为 "true"，只在等待期间存在
 }
 type File struct {
-	Path string `json:"path"`
+	Path     string `json:"path"`
+	Selected string `json:"selected"` // 是否选择下载："true" 或 "false"
 }
 
 // URI URI信息结构体
@@ -52,10 +64,33 @@ type Info struct {
 // DownloadCallback 下载回调函数类型
 type DownloadCallback func(status *DownloadStatus)
 
+// StartCallback 下载开始回调函数类型
+type StartCallback func(gid, filename string, totalBytes int64)
+
 // DownloadResult 下载结果结构体
 type DownloadResult struct {
 	Status *DownloadStatus
-	Error  error
+	Dir    string // 任务使用的下载目录
+	Timing Timing // 任务各阶段的时间
+	// WasResumed 是否复用了已有的部分文件（断点续传），而不是从头下载
+	// 只有指定了输出文件名时才能在添加任务前检测到已有文件；
+	// 没有 .aria2 控制文件的部分文件只有在服务器支持 Range 请求时才会被复用
+	WasResumed bool
+	Metadata   map[string]string // 添加任务时指定的元数据
+	// NotModified 启用 WithConditionalGet 时，远程文件不比本地新、没有重新下载
+	NotModified bool
+	Error       error
+
+	// 以下字段根据任务结束时的状态填充。下载成功时都有值；
+	// 出错或被取消时只包含已知的信息（例如尚未得知文件大小时 TotalBytes 为 0）；
+	// 添加任务失败时都为零值
+	Path         string        // 第一个文件的完整路径
+	TotalBytes   int64         // 服务器报告的文件总大小
+	AverageSpeed int64         // 平均下载速度（字节/秒），按本次下载的字节数和下载时间计算，不含断点续传已有的部分
+	Duration     time.Duration // 从提交任务到结束的时间，同 Timing.Duration
+	Connections  int           // 下载过程中观察到的最大连接数
+	IsTorrent    bool          // 是否为 BitTorrent 任务
+	MirrorsTried []string      // 因速度过低换用过的镜像，见 WithMirrorFallback
 }
 
 type Aria2 struct {
@@ -66,27 +101,60 @@ type Aria2 struct {
 	ctx        context.Context
 	cancel     context.CancelFunc
 	httpClient *http.Client
+	cfg        config
+	started    map[string]bool // 已触发开始回调的任务
+	btPort     int             // 已分配的 BitTorrent 监听端口
+	bandwidth  bandwidthStats  // 全局速度采样
+	draining   bool            // 正在排空，不再接受新任务
+	concurrent int             // 运行时设置的最大同时下载任务数，0 表示使用启动配置
+	requestID  atomic.Uint64   // JSON-RPC 请求序号
+	metaMu     sync.Mutex
+	metadata   metadataRegistry // 任务元数据
+	origin     DaemonOrigin     // aria2c 进程的来源
+	pollMu     sync.Mutex
+	poller     statusPoller // 共享的任务状态轮询器
+
+	exited       chan struct{} // aria2c 进程退出后关闭
+	ephemeralDir string        // 本次启动使用的临时目录，交给 monitor 删除
+	meteredMu    sync.Mutex
+	metered      meteredState // 计费网络模式及进入前的状态
+	ioStatsMu    sync.Mutex
+	ioStats      ioStatsState // IOStats 的日志解析进度
+	compatMu     sync.Mutex
+	compat       compatState // 连接的 aria2 的版本和兼容性警告
+	rpcOnce      sync.Once
+	rpcSlots     chan struct{} // 限制同时进行的 RPC 请求数
+	inFlight     atomic.Int64  // 正在进行的 RPC 请求数
+	malformedMu  sync.Mutex
+	malformed    map[string]bool // 已经警告过的无法解析的数值
 }
 
-// 全局实例
-var aria2 = newDaemon()
+// 全局实例，首次使用包级别函数时才创建，避免占用其他实例的端口
+var (
+	defaultOnce   sync.Once
+	defaultDaemon *Aria2
+)
+
+// getDefault 获取包级别函数使用的全局实例
+func getDefault() *Aria2 {
+	defaultOnce.Do(func() {
+		defaultDaemon = newDaemon()
+	})
+	return defaultDaemon
+}
 
 // Download 包级别的下载函数，可以直接调用
 func Download(url string, dir string, callback DownloadCallback) (string, error) {
+	aria2 := getDefault()
 	if !aria2.IsRunning() {
 		if err := aria2.Start(); err != nil {
 			return "", err
 		}
 	}
-	gid, err := aria2.AddUri(url, dir)
-	if err != nil {
-		return "", err
-	}
-
-	return aria2.monitorDownload(gid, callback)
+	return aria2.download([]string{url}, DownloadOptions{Dir: dir}, callback)
 }
 func Stop() {
-	aria2.Stop()
+	getDefault().Stop()
 }
 
 func newDaemon() *Aria2 {
@@ -99,6 +167,8 @@ func newDaemon() *Aria2 {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		cfg:     defaultConfig(),
+		started: make(map[string]bool),
 	}
 }
 
@@ -117,29 +187,70 @@ func (a *Aria2) IsRunning() bool {
 	return a.running
 }
 
+// Start 启动 aria2c 并等待 RPC 服务就绪，相当于依次调用 Spawn 和 WaitReady
 func (a *Aria2) Start() error {
+	// 尽早发现没有可用二进制文件的情况，而不是在提取时才报错
+	if err := a.checkBinaryAvailable(); err != nil {
+		return err
+	}
+	if err := a.Spawn(); err != nil {
+		return err
+	}
+
+	// 等待RPC服务启动
+	if err := a.waitForRPC(); err != nil {
+		a.Stop()
+		return fmt.Errorf("RPC service failed to start: %w", err)
+	}
+	return nil
+}
+
+// Spawn 只启动 aria2c 进程，不等待 RPC 服务就绪
+// 需要在调用其他方法前调用 WaitReady
+func (a *Aria2) Spawn() error {
+	// 下载二进制文件可能需要几分钟，在加锁前完成，期间不会阻塞 Stop 等调用
+	remotePath := a.resolveRemoteBinary()
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
-	println("启动Aria2c")
 	if a.running {
 		return fmt.Errorf("aria2c已经运行")
 	}
+	a.draining = false
 
-	binaryPath, err := ExtractBinary()
+	if err := a.prepareNetrc(); err != nil {
+		return err
+	}
+	if err := a.checkInterfaces(); err != nil {
+		return err
+	}
+	binaryPath, err := a.extractBinary(remotePath)
 	if err != nil {
 		return err
 	}
-	args := a.buildArgs()
+	// 启动失败时删除临时目录，启动成功后由 monitor 负责删除
+	defer func() {
+		if a.ephemeralDir != "" {
+			os.RemoveAll(a.ephemeralDir)
+			a.ephemeralDir = ""
+		}
+	}()
+	// 启动前再次确认二进制文件仍然存在（可能被杀毒软件隔离）
+	if err := a.ensureBinary(binaryPath); err != nil {
+		return err
+	}
+	args := a.supportedArgs(a.buildArgs(), binaryVersion(binaryPath))
 	a.cmd = exec.Command(binaryPath, args...)
 	// 在 Windows 上隐藏控制台窗口
-	if a.cmd.SysProcAttr == nil {
-		a.cmd.SysProcAttr = &syscall.SysProcAttr{}
-	}
-	a.cmd.SysProcAttr.HideWindow = true
+	hideWindow(a.cmd)
 
 	if err := a.cmd.Start(); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrBinaryQuarantined, binaryPath)
+		}
 		return fmt.Errorf("安装失败: %v", err)
 	}
+	a.applyPriority(a.cmd.Process.Pid)
 
 	// ctx, cancel := context.WithCancel(context.Background())
 	// a.ctx = ctx
@@ -157,13 +268,13 @@ func (a *Aria2) Start() error {
 	// 	return fmt.Errorf("aria2c 进程启动失败: %v", err)
 	// }
 
-	// 等待RPC服务启动
-	if err := a.waitForRPC(); err != nil {
-		return fmt.Errorf("RPC service failed to start: %w", err)
-	}
-
 	a.running = true
-	go a.monitor()
+	a.origin = OriginLaunched
+	a.exited = make(chan struct{})
+	go a.monitor(a.cmd, a.ephemeralDir, a.exited)
+	a.ephemeralDir = ""
+	go a.sampleBandwidth()
+	go a.flushSession()
 	// 启动进程监控
 	// a.processMonitor = make(chan struct{})
 	// go a.monitorProcess()
@@ -171,22 +282,48 @@ func (a *Aria2) Start() error {
 	return nil
 }
 
-// monitor 监控进程状态
-func (a *Aria2) monitor() {
-	if a.cmd != nil {
-		a.cmd.Wait()
-		a.Stop()
+// Attach 连接到已经在运行的 aria2 RPC 服务（使用 WithPort 指定的端口），不启动新进程
+func (a *Aria2) Attach() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.running {
+		return fmt.Errorf("aria2c已经运行")
+	}
+	a.draining = false
+
+	if err := a.waitForRPC(); err != nil {
+		return fmt.Errorf("连接RPC服务失败: %w", err)
+	}
+
+	a.running = true
+	a.origin = OriginAttached
+	go a.sampleBandwidth()
+	go a.flushSession()
+	return nil
+}
+
+// monitor 监控进程状态，进程退出后删除临时目录（如果有）并关闭 exited
+func (a *Aria2) monitor(cmd *exec.Cmd, ephemeralDir string, exited chan struct{}) {
+	defer close(exited)
+	cmd.Wait()
+	a.Stop()
+	if ephemeralDir != "" {
+		if err := removeEphemeralDir(ephemeralDir); err != nil {
+			a.logf("%v", err)
+		}
 	}
 }
 
-// 修改 Stop 方法
+// Stop 停止 aria2c 进程，可以重复调用
+// 进程已经退出（包括重复调用、monitor 协程与调用方同时停止）时视为成功并返回 nil，
+// 只有真正无法结束进程时才返回错误
 func (a *Aria2) Stop() error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	a.running = false
+	a.origin = OriginNone
 	if a.cmd != nil && a.cmd.Process != nil {
-		if err := a.cmd.Process.Kill(); err != nil {
-			println("3333", "failed to kill aria2c process: %w", err)
+		if err := a.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
 			return fmt.Errorf("failed to kill aria2c process: %w", err)
 		}
 	}
@@ -194,23 +331,70 @@ func (a *Aria2) Stop() error {
 	return nil
 }
 
+// reservedPorts 本进程内各实例已分配的端口
+// 多个实例在启动前分配端口时，端口尚未被监听，需要记录下来避免分配到同一个端口
+var (
+	reservedMu    sync.Mutex
+	reservedPorts = make(map[int]bool)
+)
+
 func findAvailablePort(port int) int {
+	return findAvailablePortOn("tcp", port)
+}
+
+// findAvailablePortOn 从 port 开始寻找 network（tcp、tcp4 或 tcp6）上可用的端口
+func findAvailablePortOn(network string, port int) int {
+	reservedMu.Lock()
+	defer reservedMu.Unlock()
+	for reservedPorts[port] || !isPortAvailableOn(network, port) {
+		// 端口被占用
+		port++
+	}
+	reservedPorts[port] = true
+	return port
+}
+
+// releasePort 释放已分配的端口
+func releasePort(port int) {
+	reservedMu.Lock()
+	delete(reservedPorts, port)
+	reservedMu.Unlock()
+}
+
+// findAvailablePortInRange 在 [start, end] 范围内寻找可用端口
+func findAvailablePortInRange(start, end int) (int, bool) {
+	reservedMu.Lock()
+	defer reservedMu.Unlock()
+	for port := start; port <= end; port++ {
+		if !reservedPorts[port] && isPortAvailable(port) {
+			reservedPorts[port] = true
+			return port, true
+		}
+	}
+	return 0, false
+}
+
+// isPortAvailable 检查端口是否可用
+func isPortAvailable(port int) bool {
+	return isPortAvailableOn("tcp", port)
+}
+
+// isPortAvailableOn 检查 network 上的端口是否可用
+func isPortAvailableOn(network string, port int) bool {
 	// 尝试监听该端口
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	listener, err := net.Listen(network, fmt.Sprintf(":%d", port))
 	if err != nil {
-		// 端口被占用
-		return findAvailablePort(port + 1)
+		return false
 	}
 	// 端口可用，立即关闭监听器
 	listener.Close()
-	return port
+	return true
 }
 
 // buildArgs 构建命令行参数
 func (a *Aria2) buildArgs() []string {
 	args := []string{
 		"--rpc-listen-port=" + strconv.Itoa(a.port),
-		"--disk-cache=64M",             // 磁盘缓存 有足够的内存空闲情况下适当增加
 		"--always-resume=false",        // 始终尝试断点续传，无法断点续传则终止下载，默认：true
 		"--max-resume-failure-tries=0", // 值为 0 时所有 URI 不支持断点续传时才从头开始下载
 		"--enable-rpc=true",            //
@@ -223,41 +407,129 @@ func (a *Aria2) buildArgs() []string {
 		"--log-level=error",
 		"--http-accept-gzip=true",                 // GZip 支持，默认:false
 		"--content-disposition-default-utf8=true", //使用 UTF-8 处理 Content-Disposition ，默认:false
-		"--check-certificate=false",               // 禁用SSL证书验证
 	}
-
-	return args
+	// 磁盘缓存 有足够的内存空闲情况下适当增加，0 表示禁用
+	args = append(args, "--disk-cache="+strconv.Itoa(a.cfg.diskCache))
+	args = append(args, "--file-allocation="+a.cfg.fileAllocation)
+
+	// BitTorrent 监听端口：优先使用范围内第一个可用端口，找不到时交给 aria2 在范围内自行选择
+	if a.cfg.btListenPortStart > 0 {
+		if a.btPort == 0 {
+			a.btPort, _ = findAvailablePortInRange(a.cfg.btListenPortStart, a.cfg.btListenPortEnd)
+		}
+		if a.btPort > 0 {
+			args = append(args, "--listen-port="+strconv.Itoa(a.btPort))
+		} else {
+			args = append(args, fmt.Sprintf("--listen-port=%d-%d", a.cfg.btListenPortStart, a.cfg.btListenPortEnd))
+		}
+	}
+	if a.cfg.btMaxPeers >= 0 {
+		args = append(args, "--bt-max-peers="+strconv.Itoa(a.cfg.btMaxPeers))
+	}
+	if a.cfg.maxOverallUploadLimit > 0 {
+		args = append(args, "--max-overall-upload-limit="+strconv.Itoa(a.cfg.maxOverallUploadLimit))
+	}
+	if a.cfg.autoSaveInterval >= 0 {
+		args = append(args, "--auto-save-interval="+strconv.Itoa(a.cfg.autoSaveInterval))
+	}
+	if a.cfg.saveSession != "" {
+		args = append(args, "--save-session="+a.cfg.saveSession)
+		// 会话文件存在时启动时恢复上次保存的任务
+		if _, err := os.Stat(a.cfg.saveSession); err == nil {
+			args = append(args, "--input-file="+a.cfg.saveSession)
+		}
+	}
+	if a.cfg.secret != "" {
+		args = append(args, "--rpc-secret="+a.cfg.secret)
+	}
+	if a.cfg.maxConcurrentDownloads > 0 {
+		args = append(args, "--max-concurrent-downloads="+strconv.Itoa(a.cfg.maxConcurrentDownloads))
+	}
+	if a.cfg.netrcPath != "" {
+		args = append(args, "--no-netrc=false", "--netrc-path="+a.cfg.netrcPath)
+	}
+	if a.cfg.allowPieceLengthChange {
+		args = append(args, "--allow-piece-length-change=true")
+	}
+	args = append(args, a.interfaceArgs()...)
+	if a.cfg.logFile != "" {
+		args = append(args, "--log="+a.cfg.logFile)
+	}
+	if a.cfg.logLevel != "" {
+		args = setArg(args, "log-level", a.cfg.logLevel)
+	}
+	if a.cfg.checkIntegrity {
+		args = append(args, "--check-integrity=true")
+	}
+	if a.cfg.seedUnverified {
+		args = append(args, "--check-integrity=false", "--bt-seed-unverified=true")
+	}
+	args = append(args, a.ipVersionArgs()...)
+	args = append(args, a.conditionalArgs()...)
+	args = append(args, a.rpcTLSArgs()...)
+	args = append(args, a.retryArgs()...)
+	args = append(args, a.pieceSelectorArgs()...)
+	args = append(args, a.uriSelectorArgs()...)
+	args = append(args, a.certificateArgs()...)
+	args = append(args, a.saveSessionArgs()...)
+	args = append(args, a.hookArgs()...)
+	args = append(args, a.dnsArgs()...)
+
+	args = a.failFastArgs(args)
+
+	return a.connectionArgs(args)
 }
 
-// waitForRPC 等待RPC服务启动
-// 这个函数会持续检查 aria2c 的 RPC 服务是否已经启动并可以接受连接
+// waitForRPC 等待RPC服务启动，最多等待10秒
 func (a *Aria2) waitForRPC() error {
-	timeout := time.After(10 * time.Second)
-	ticker := time.NewTicker(100 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(a.ctx, 10*time.Second)
+	defer cancel()
+	return a.WaitReady(ctx)
+}
+
+// WaitReady 等待 RPC 服务就绪，直到 ctx 结束
+// 这个函数会持续调用 aria2.getVersion 检查 aria2c 的 RPC 服务是否已经启动并可以正常响应，
+// 仅端口可连接不代表就绪（端口可能被其他程序占用）。就绪后检查 aria2 的版本兼容性，见 CompatibilityWarnings
+func (a *Aria2) WaitReady(ctx context.Context) error {
+	ticker := a.clock().NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-timeout:
-			// 如果超过10秒超时时间，返回超时错误
-			return fmt.Errorf("等待RPC服务超时")
-		case <-ticker.C:
-			// 每100毫秒执行一次：尝试连接到 aria2c 的 RPC 端口
-			conn, err := net.DialTimeout("tcp", fmt.Sprintf("localhost:%d", a.port), time.Second)
-			if err == nil {
-				// 如果连接成功（err == nil），说明 RPC 服务已经启动
-				// 立即关闭连接（因为我们只是测试连接，不需要保持连接）
-				conn.Close()
-				// 返回 nil 表示成功，函数结束
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				// 如果超过超时时间，返回超时错误
+				return fmt.Errorf("等待RPC服务超时")
+			}
+			return fmt.Errorf("ctx上下文已取消")
+		case <-ticker.C():
+			// 每100毫秒执行一次：发送一次真实的 RPC 请求
+			if a.probeRPC(ctx) == nil {
+				a.checkCompatibility()
 				return nil
 			}
-			// 如果连接失败，继续下一次循环（100毫秒后再次尝试）
+			// 如果请求失败，继续下一次循环（100毫秒后再次尝试）
 		case <-a.ctx.Done():
 			// 如果上下文被取消（比如程序被中断），返回上下文取消错误
 			return fmt.Errorf("ctx上下文已取消")
 		}
 	}
+}
+
+// probeRPC 调用 aria2.getVersion 探测 RPC 服务，只有真正的 aria2 响应才算成功
+func (a *Aria2) probeRPC(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
 
+	result, err := a.call(ctx, "aria2.getVersion", nil)
+	if err != nil {
+		return err
+	}
+	var version VersionInfo
+	if err := json.Unmarshal(result, &version); err != nil || version.Version == "" {
+		return fmt.Errorf("端口 %d 上的服务不是 aria2", a.port)
+	}
+	return nil
 }
 
 type jsonRPCRequest struct {
@@ -271,37 +543,55 @@ type jsonRPCRequest struct {
 type jsonRPCResponse struct {
 	JSONRPC string          `json:"jsonrpc"`
 	Result  json.RawMessage `json:"result,omitempty"`
-	Error   *jsonRPCError   `json:"error,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
 	ID      string          `json:"id"`
 }
 
-// JSONRPCError JSON-RPC 错误结构
-type jsonRPCError struct {
+// RPCError aria2 返回的 JSON-RPC 错误
+// 与网络错误不同，RPC 错误表示请求已被 aria2 处理但执行失败，重试通常没有意义
+type RPCError struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
 }
 
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("JSON-RPC错误 %d: %s", e.Code, e.Message)
+}
+
 func (a *Aria2) Call(method string, params []interface{}) (json.RawMessage, error) {
+	return a.call(context.Background(), method, params)
+}
+
+// call 发送 JSON-RPC 请求，ctx 用于控制单次请求的超时和取消
+func (a *Aria2) call(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+	if params == nil {
+		params = []interface{}{}
+	}
+	params = a.withToken(method, params)
 	req := jsonRPCRequest{
 		JSONRPC: "2.0",
 		Method:  method,
 		Params:  params,
-		ID:      strconv.FormatInt(time.Now().UnixNano(), 10),
+		ID:      a.nextRequestID(ctx),
 	}
 	reqBody, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("序列化请求失败: %w", err)
 	}
-	url := fmt.Sprintf("http://127.0.0.1:%d/jsonrpc", a.port)
+	url := a.rpcURL("http")
 	// 发送 HTTP 请求
-	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return nil, fmt.Errorf("创建HTTP请求失败: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	// 执行请求
+	// 执行请求，同时进行的请求数超过上限时排队
+	if err := a.acquireRPC(ctx); err != nil {
+		return nil, err
+	}
+	defer a.releaseRPC()
 	resp, err := a.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("HTTP请求失败: %w", err)
@@ -320,20 +610,54 @@ func (a *Aria2) Call(method string, params []interface{}) (json.RawMessage, erro
 		return nil, fmt.Errorf("解析响应失败: %w", err)
 	}
 
+	// 响应必须与请求对应，防止连接复用或代理异常时收到其他请求的响应
+	// aria2 无法解析请求时返回的错误响应 id 为 null，不做检查
+	if rpcResp.ID != req.ID && (rpcResp.Error == nil || rpcResp.ID != "") {
+		return nil, fmt.Errorf("%w: 请求 %s，响应 %s", ErrResponseIDMismatch, req.ID, rpcResp.ID)
+	}
+
 	// 检查错误
 	if rpcResp.Error != nil {
-		return nil, fmt.Errorf("JSON-RPC错误 %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
+		return nil, rpcResp.Error
 	}
 
 	return rpcResp.Result, nil
 }
 
+// AddUri 添加下载任务，dir 为空时使用默认下载目录
 func (a *Aria2) AddUri(uri string, dir string) (string, error) {
+	dir, err := a.ResolveDir(dir)
+	if err != nil {
+		return "", err
+	}
+	return a.addUri([]string{uri}, map[string]interface{}{
+		"dir": dir,
+	})
+}
+
+// AddUriMirrors 添加一个下载任务，uris 中的所有地址作为同一文件的镜像
+// aria2 会在多个镜像间并行下载并在某个镜像失败时自动切换
+func (a *Aria2) AddUriMirrors(uris []string, dir, out string) (string, error) {
+	return a.AddUriWithOptions(uris, DownloadOptions{
+		Dir: dir,
+		Out: out,
+	})
+}
+
+// addUri 调用 aria2.addUri 添加下载任务并返回 GID
+func (a *Aria2) addUri(uris []string, options map[string]interface{}) (string, error) {
+	if err := a.checkAccepting(); err != nil {
+		return "", err
+	}
+	// 认证信息通过选项传递，避免出现在任务地址和日志中
+	uris, options, err := moveCredentials(uris, options)
+	if err != nil {
+		return "", err
+	}
+	options = a.connectionOptions(options)
 	result, err := a.Call("aria2.addUri", []interface{}{
-		[]string{uri}, // 第一个参数：URL数组
-		map[string]interface{}{ // 第二个参数：选项对象
-			"dir": dir,
-		},
+		uris,    // 第一个参数：URL数组
+		options, // 第二个参数：选项对象
 	})
 	if err != nil {
 		return "", err
@@ -345,7 +669,24 @@ func (a *Aria2) AddUri(uri string, dir string) (string, error) {
 	return gid, nil
 }
 
-// TellStatus 获取下载任务状态
+// validateURI 检查下载地址格式是否正确
+func validateURI(uri string) error {
+	u, err := url.Parse(uri)
+	if err != nil {
+		// url.Error 中包含完整地址，只保留具体原因
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("无效的下载地址 %q: %w", RedactURL(uri), err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("无效的下载地址 %q: 缺少协议或主机名", RedactURL(uri))
+	}
+	return nil
+}
+
+// TellStatus 获取下载任务状态（返回全部字段）
 func (a *Aria2) TellStatus(gid string) (*DownloadStatus, error) {
 	result, err := a.Call("aria2.tellStatus", []interface{}{gid})
 	if err != nil {
@@ -358,33 +699,263 @@ func (a *Aria2) TellStatus(gid string) (*DownloadStatus, error) {
 	return &status, nil
 }
 
+// TellStatusKeys 获取下载任务状态，只返回 keys 中指定的字段（如 "gid"、"status"、"completedLength"）
+// 高频轮询大量任务时可以显著减少响应大小，未请求的字段保持零值
+func (a *Aria2) TellStatusKeys(gid string, keys []string) (*DownloadStatus, error) {
+	result, err := a.Call("aria2.tellStatus", []interface{}{gid, keys})
+	if err != nil {
+		return nil, err
+	}
+	var status DownloadStatus
+	if err := json.Unmarshal(result, &status); err != nil {
+		return nil, fmt.Errorf("解析状态失败: %w", err)
+	}
+	return &status, nil
+}
+
+// withToken 设置了 RPC 密钥时，在 aria2.* 方法的参数前加上 token
+// system.* 方法不需要 token，system.multicall 中的每个调用需要单独添加
+func (a *Aria2) withToken(method string, params []interface{}) []interface{} {
+	if a.cfg.secret == "" || !strings.HasPrefix(method, "aria2.") {
+		return params
+	}
+	return append([]interface{}{"token:" + a.cfg.secret}, params...)
+}
+
+// extractBinary 提取二进制文件，设置了 WithDataDir 时提取到指定目录
+// remotePath 为 Spawn 加锁前准备好的下载版二进制文件，不为空时直接使用
+// 设置了 WithRemoteBinary 时优先使用下载的版本
+func (a *Aria2) extractBinary(remotePath string) (string, error) {
+	if remotePath != "" {
+		return remotePath, nil
+	}
+	if a.cfg.ephemeralBinary {
+		return a.extractEphemeral()
+	}
+	if a.cfg.dataDir != "" {
+		return extractBinary(a.cfg.dataDir)
+	}
+	return ExtractBinary()
+}
+
 // monitorDownload 监控下载状态直到完成或出错（同步版本）
 func (a *Aria2) monitorDownload(gid string, callback DownloadCallback) (string, error) {
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
+	status, err := a.watchDownload(&watch{gid: gid, callback: callback})
+	if err != nil {
+		return "", err
+	}
+	return status.path(), nil
+}
+
+// watch 单个任务的监控参数和状态
+type watch struct {
+	gid        string
+	callback   DownloadCallback
+	progress   progressTracker
+	lastNotify time.Time // 上次回调的时间
+	timing     Timing
+	metadata   map[string]string
+	lastStatus string // 上次查询到的状态
+	splitTuned bool   // 已按文件大小调整过分段数
+	maxConns   int    // 观察到的最大连接数
+	sizeKnown  bool   // 已得知文件大小并检查过大小上限
+	mirror     mirrorState
+
+	localPath   string      // 条件下载时本地文件的路径
+	localBefore os.FileInfo // 条件下载前本地文件的状态
+}
 
+// result 生成任务结束时的结果
+func (w *watch) result(dir string, status *DownloadStatus, err error) DownloadResult {
+	r := DownloadResult{
+		Status:      status,
+		Dir:         dir,
+		Timing:      w.timing,
+		WasResumed:  w.progress.wasResumed(),
+		Metadata:    w.metadata,
+		NotModified: err == nil && w.notModified(status),
+		Error:       err,
+		Duration:    w.timing.Duration(),
+		Connections: w.maxConns,
+	}
+	r.MirrorsTried = w.mirror.tried
+	r.fillFromStatus(status)
+	if status != nil {
+		elapsed := w.timing.CompletedAt.Sub(w.timing.StartedAt)
+		if w.timing.StartedAt.IsZero() || w.timing.CompletedAt.IsZero() {
+			elapsed = r.Duration
+		}
+		if elapsed > 0 {
+			bytes := w.progress.sessionBytes(parseLength(status.CompletedLength))
+			r.AverageSpeed = int64(float64(bytes) / elapsed.Seconds())
+		}
+	}
+	return r
+}
+
+// fillFromStatus 根据最终状态填充路径、大小等信息
+func (r *DownloadResult) fillFromStatus(status *DownloadStatus) {
+	if status == nil {
+		return
+	}
+	r.Path = status.path()
+	r.TotalBytes = parseLength(status.TotalLength)
+	r.IsTorrent = status.InfoHash != ""
+	if n, err := strconv.Atoi(status.Connections); err == nil && n > r.Connections {
+		r.Connections = n
+	}
+}
+
+// watchDownload 监控下载状态直到任务结束，返回最终状态
+func (a *Aria2) watchDownload(w *watch) (*DownloadStatus, error) {
+	gid := w.gid
+	w.metadata = a.Metadata(gid)
+
+	// 先查询再等待：文件已下载过时任务可能在第一次查询时就已完成
+	status, err := a.tellStatusRetry(gid)
+	if err != nil {
+		return nil, err
+	}
+
+	// 之后的状态由共享轮询器批量查询
+	var updates chan pollResult
+	failures := 0
 	for {
-		select {
-		case <-ticker.C:
-			status, err := a.TellStatus(gid)
-			if err != nil {
-				return "", err
+		if status != nil {
+			if done, err := a.handleStatus(w, status); done {
+				return status, err
 			}
+		}
 
-			// 调用回调函数
-			if callback != nil {
-				callback(status)
+		if updates == nil {
+			updates = a.subscribe(gid)
+			defer a.unsubscribe(gid, updates)
+		}
+		select {
+		case r := <-updates:
+			if r.err != nil {
+				// 临时网络错误时等待下一次轮询，连续失败次数用尽或服务已停止时返回
+				failures++
+				if !isTransientError(r.err) || failures > maxPollRetries || !a.IsRunning() {
+					return nil, r.err
+				}
+				status = nil
+				continue
 			}
+			failures = 0
+			status = r.status
+		case <-a.ctx.Done():
+			return status, fmt.Errorf("ctx上下文已取消")
+		}
+	}
+}
 
-			// 检查是否完成或出错
-			switch status.Status {
-			case "complete":
-				return status.Files[0].Path, nil
-			case "error":
-				return "", fmt.Errorf("下载出错: %s", status.ErrorMessage)
-			}
+// handleStatus 处理一次查询到的状态：触发各个回调，任务结束时返回 done 和最终错误
+func (a *Aria2) handleStatus(w *watch, status *DownloadStatus) (done bool, err error) {
+	gid := w.gid
+	a.warnMalformed(status)
+	a.notifyStart(status)
+	a.notifyStatusChange(w, status)
+	now := a.clock().Now()
+	w.timing.observe(status, now)
+	if err := a.checkFileSize(w, status); err != nil {
+		a.forgetStart(gid)
+		return true, err
+	}
+	a.tuneSplit(w, status)
+	a.checkMirror(w, status, now)
+	if n, err := strconv.Atoi(status.Connections); err == nil && n > w.maxConns {
+		w.maxConns = n
+	}
+
+	// 调用回调函数
+	if w.shouldNotify(status, a.cfg.callbackThrottle, now) {
+		if w.callback != nil {
+			w.callback(status)
+		}
+		progress := w.progress.update(status, now)
+		progress.Metadata = w.metadata
+		if a.cfg.onProgress != nil {
+			a.cfg.onProgress(progress)
+		}
+	}
+
+	// 检查是否完成或出错
+	switch status.Status {
+	case "complete":
+		a.forgetStart(gid)
+		return true, a.checkContent(status)
+	case "error":
+		a.forgetStart(gid)
+		return true, newDownloadError(status)
+	case "removed":
+		a.forgetStart(gid)
+		return true, fmt.Errorf("下载已取消")
+	}
+	return false, nil
+}
+
+// notifyStart 任务首次进入 active 状态且元数据已知时触发开始回调
+func (a *Aria2) notifyStart(status *DownloadStatus) {
+	if a.cfg.onStart == nil || status.Status != "active" || len(status.Files) == 0 {
+		return
+	}
+	totalBytes := parseLength(status.TotalLength)
+	path := status.Files[0].Path
+	if totalBytes <= 0 || path == "" {
+		return
+	}
+
+	a.mu.Lock()
+	if a.started[status.GID] {
+		a.mu.Unlock()
+		return
+	}
+	a.started[status.GID] = true
+	a.mu.Unlock()
+
+	a.cfg.onStart(status.GID, filepath.Base(path), totalBytes)
+}
+
+// forgetStart 任务结束后清除开始回调记录
+func (a *Aria2) forgetStart(gid string) {
+	a.mu.Lock()
+	delete(a.started, gid)
+	a.mu.Unlock()
+}
+
+// maxPollRetries 轮询状态遇到临时网络错误时的最大重试次数
+const maxPollRetries = 3
+
+// tellStatusRetry 查询任务状态，遇到连接失败、超时等临时网络错误时退避重试
+// RPC 错误直接返回；重试次数用尽或服务已停止时返回最后一次的错误
+func (a *Aria2) tellStatusRetry(gid string) (*DownloadStatus, error) {
+	backoff := 500 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		status, err := a.TellStatus(gid)
+		if err == nil || !isTransientError(err) || attempt >= maxPollRetries || !a.IsRunning() {
+			return status, err
+		}
+
+		select {
+		case <-a.clock().After(backoff):
+			backoff *= 2
 		case <-a.ctx.Done():
-			return "", fmt.Errorf("ctx上下文已取消")
+			return nil, err
 		}
 	}
 }
+
+// isTransientError 判断是否为可重试的临时网络错误（连接被拒绝、超时、连接中断等）
+// http.Client 的所有错误都包装为实现了 net.Error 的 *url.Error，证书校验失败等不会自行恢复的错误不能重试
+func isTransientError(err error) bool {
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return isConnRefusedOrReset(err) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
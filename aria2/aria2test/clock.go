@@ -0,0 +1,126 @@
+package aria2test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dxcweb/go-aria2/aria2"
+)
+
+// Clock 手动推进的时间来源，配合 aria2.WithClock 使用
+// 只有调用 Advance 时时间才会前进，到期的定时器在 Advance 中触发
+type Clock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*clockWaiter
+}
+
+// clockWaiter 一个未触发的 After 或 Ticker
+type clockWaiter struct {
+	at     time.Time
+	period time.Duration // 大于 0 表示 Ticker
+	ch     chan time.Time
+}
+
+// NewClock 创建从 start 开始的时间来源
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+// Now 返回当前时间
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After 在时间推进 d 之后发送当前时间
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := &clockWaiter{at: c.now.Add(d), ch: make(chan time.Time, 1)}
+	c.waiters = append(c.waiters, w)
+	return w.ch
+}
+
+// NewTicker 创建每推进 d 触发一次的 Ticker，与 time.Ticker 一样，接收方来不及读取时丢弃多余的触发
+func (c *Clock) NewTicker(d time.Duration) aria2.Ticker {
+	if d <= 0 {
+		panic("aria2test: NewTicker 的间隔必须大于 0")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := &clockWaiter{at: c.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	c.waiters = append(c.waiters, w)
+	return &fakeTicker{clock: c, w: w}
+}
+
+// Advance 将时间推进 d，并按到期时间顺序触发所有到期的定时器
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	end := c.now.Add(d)
+	for {
+		next := c.nextDue(end)
+		if next == nil {
+			break
+		}
+		c.now = next.at
+		select {
+		case next.ch <- c.now:
+		default:
+		}
+		if next.period > 0 {
+			next.at = next.at.Add(next.period)
+		} else {
+			c.remove(next)
+		}
+	}
+	c.now = end
+}
+
+// Waiters 返回尚未触发的 After 和未停止的 Ticker 数量，
+// 测试可以轮询它确认被测代码已经开始等待后再调用 Advance
+func (c *Clock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// nextDue 返回 end 之前最早到期的定时器
+func (c *Clock) nextDue(end time.Time) *clockWaiter {
+	var next *clockWaiter
+	for _, w := range c.waiters {
+		if w.at.After(end) {
+			continue
+		}
+		if next == nil || w.at.Before(next.at) {
+			next = w
+		}
+	}
+	return next
+}
+
+// remove 删除定时器
+func (c *Clock) remove(target *clockWaiter) {
+	for i, w := range c.waiters {
+		if w == target {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// fakeTicker Clock 创建的 Ticker
+type fakeTicker struct {
+	clock *Clock
+	w     *clockWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.w.ch }
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.clock.remove(t.w)
+}
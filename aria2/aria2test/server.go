@@ -0,0 +1,370 @@
+// Package aria2test 提供模拟 aria2 JSON-RPC 服务的测试服务器，
+// 无需真实的 aria2c 即可测试下载流程。
+package aria2test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dxcweb/go-aria2/aria2"
+)
+
+// Script 根据 addUri 的参数生成任务状态序列
+// 每次 tellStatus 依次返回下一个状态，最后一个状态会一直保持
+type Script func(uris []string, options map[string]interface{}) []aria2.DownloadStatus
+
+// DefaultScript 默认脚本：下载中（一半进度）→ 完成
+func DefaultScript(uris []string, options map[string]interface{}) []aria2.DownloadStatus {
+	dir, _ := options["dir"].(string)
+	out, _ := options["out"].(string)
+	if out == "" && len(uris) > 0 {
+		out = path.Base(uris[0])
+	}
+	files := []aria2.File{{Path: filepath.Join(dir, out), Selected: "true"}}
+	return []aria2.DownloadStatus{
+		{Status: "active", TotalLength: "1024", CompletedLength: "512", DownloadSpeed: "512", Files: files},
+		{Status: "complete", TotalLength: "1024", CompletedLength: "1024", Files: files},
+	}
+}
+
+// task 模拟的下载任务
+type task struct {
+	gid     string
+	uris    []string
+	options map[string]interface{}
+	steps   []aria2.DownloadStatus
+	step    int
+	paused  bool
+	removed bool
+}
+
+// status 返回当前状态，暂停和删除会覆盖脚本中的状态
+func (t *task) status() aria2.DownloadStatus {
+	status := t.steps[t.step]
+	status.GID = t.gid
+	switch {
+	case t.removed:
+		status.Status = "removed"
+	case t.paused:
+		status.Status = "paused"
+	}
+	return status
+}
+
+// advance 前进到下一个状态
+func (t *task) advance() {
+	if !t.paused && !t.removed && t.step < len(t.steps)-1 {
+		t.step++
+	}
+}
+
+// Server 模拟 aria2 JSON-RPC 服务的测试服务器
+type Server struct {
+	*httptest.Server
+
+	script Script
+
+	mu      sync.Mutex
+	tasks   map[string]*task
+	order   []string
+	nextGID int
+	calls   []string
+	conns   map[net.Conn]bool // WebSocket 连接
+}
+
+// NewServer 启动测试服务器，script 为 nil 时使用 DefaultScript
+func NewServer(script Script) *Server {
+	if script == nil {
+		script = DefaultScript
+	}
+	s := &Server{
+		script: script,
+		tasks:  make(map[string]*task),
+		conns:  make(map[net.Conn]bool),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Port 返回测试服务器监听的端口
+func (s *Server) Port() int {
+	_, port, _ := strings.Cut(strings.TrimPrefix(s.URL, "http://"), ":")
+	n, _ := strconv.Atoi(port)
+	return n
+}
+
+// Options 返回连接到测试服务器所需的选项，创建实例后调用 Attach 即可使用
+func (s *Server) Options() []aria2.Option {
+	return []aria2.Option{aria2.WithPort(s.Port())}
+}
+
+// Calls 返回已收到的方法调用名称（按顺序，system.multicall 会展开为其中的各个方法）
+func (s *Server) Calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.calls...)
+}
+
+type request struct {
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+	ID     string            `json:"id"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/jsonrpc" {
+		http.NotFound(w, r)
+		return
+	}
+	if isWebSocket(r) {
+		s.upgrade(w, r)
+		return
+	}
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	result, err := s.dispatch(req.Method, req.Params)
+	if err != nil {
+		resp["error"] = err
+	} else {
+		resp["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// dispatch 执行单个方法调用
+func (s *Server) dispatch(method string, params []json.RawMessage) (interface{}, *rpcError) {
+	s.mu.Lock()
+	s.calls = append(s.calls, method)
+	s.mu.Unlock()
+
+	// 去掉 token 参数
+	if len(params) > 0 {
+		var token string
+		if json.Unmarshal(params[0], &token) == nil && strings.HasPrefix(token, "token:") {
+			params = params[1:]
+		}
+	}
+
+	switch method {
+	case "system.multicall":
+		return s.multicall(params)
+	case "aria2.getVersion":
+		return map[string]interface{}{"version": "1.37.0", "enabledFeatures": []string{}}, nil
+	case "aria2.getSessionInfo":
+		return map[string]string{"sessionId": "aria2test"}, nil
+	case "aria2.addUri":
+		return s.addUri(params)
+	case "aria2.tellStatus":
+		return s.tellStatus(params)
+	case "aria2.tellActive":
+		return s.tellByStatus("active"), nil
+	case "aria2.tellWaiting":
+		return s.tellWaiting(params)
+	case "aria2.tellStopped":
+		return append(s.tellByStatus("complete"), append(s.tellByStatus("error"), s.tellByStatus("removed")...)...), nil
+	case "aria2.remove", "aria2.forceRemove":
+		return s.update(params, func(t *task) { t.removed = true })
+	case "aria2.pause", "aria2.forcePause":
+		return s.update(params, func(t *task) { t.paused = true })
+	case "aria2.unpause":
+		return s.update(params, func(t *task) { t.paused = false })
+	case "aria2.getUris":
+		return s.getUris(params)
+	case "aria2.changeUri":
+		return s.changeUri(params)
+	case "aria2.removeDownloadResult":
+		return s.update(params, func(t *task) {})
+	case "aria2.getGlobalStat":
+		return map[string]string{
+			"downloadSpeed": "0", "uploadSpeed": "0",
+			"numActive":  strconv.Itoa(len(s.tellByStatus("active"))),
+			"numWaiting": "0", "numStopped": "0", "numStoppedTotal": "0",
+		}, nil
+	case "aria2.getGlobalOption":
+		return map[string]string{"max-overall-download-limit": "0", "max-concurrent-downloads": "5"}, nil
+	case "aria2.changeOption", "aria2.changeGlobalOption", "aria2.saveSession":
+		return "OK", nil
+	default:
+		return nil, &rpcError{Code: 1, Message: "No such method: " + method}
+	}
+}
+
+// multicall 依次执行 system.multicall 中的每个调用
+func (s *Server) multicall(params []json.RawMessage) (interface{}, *rpcError) {
+	if len(params) == 0 {
+		return nil, &rpcError{Code: 1, Message: "system.multicall 缺少参数"}
+	}
+	var calls []struct {
+		MethodName string            `json:"methodName"`
+		Params     []json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(params[0], &calls); err != nil {
+		return nil, &rpcError{Code: 1, Message: err.Error()}
+	}
+	results := make([]interface{}, len(calls))
+	for i, call := range calls {
+		result, err := s.dispatch(call.MethodName, call.Params)
+		if err != nil {
+			results[i] = err
+		} else {
+			results[i] = []interface{}{result}
+		}
+	}
+	return results, nil
+}
+
+func (s *Server) addUri(params []json.RawMessage) (interface{}, *rpcError) {
+	var uris []string
+	if len(params) == 0 || json.Unmarshal(params[0], &uris) != nil || len(uris) == 0 {
+		return nil, &rpcError{Code: 1, Message: "invalid uris"}
+	}
+	options := map[string]interface{}{}
+	if len(params) > 1 {
+		json.Unmarshal(params[1], &options)
+	}
+	steps := s.script(uris, options)
+	if len(steps) == 0 {
+		return nil, &rpcError{Code: 1, Message: "script returned no status"}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextGID++
+	gid := fmt.Sprintf("%016x", s.nextGID)
+	s.tasks[gid] = &task{
+		gid:     gid,
+		uris:    uris,
+		options: options,
+		steps:   steps,
+		paused:  options["pause"] == "true",
+	}
+	s.order = append(s.order, gid)
+	return gid, nil
+}
+
+func (s *Server) tellStatus(params []json.RawMessage) (interface{}, *rpcError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, err := s.lookup(params)
+	if err != nil {
+		return nil, err
+	}
+	status := t.status()
+	t.advance()
+	return status, nil
+}
+
+// getUris 返回任务当前的地址
+func (s *Server) getUris(params []json.RawMessage) (interface{}, *rpcError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, err := s.lookup(params)
+	if err != nil {
+		return nil, err
+	}
+	uris := make([]aria2.URI, len(t.uris))
+	for i, uri := range t.uris {
+		uris[i] = aria2.URI{URI: uri, Status: "used"}
+	}
+	return uris, nil
+}
+
+// changeUri 删除 delUris 中的地址并追加 addUris，返回删除和添加的数量
+func (s *Server) changeUri(params []json.RawMessage) (interface{}, *rpcError) {
+	var delUris, addUris []string
+	if len(params) < 4 || json.Unmarshal(params[2], &delUris) != nil || json.Unmarshal(params[3], &addUris) != nil {
+		return nil, &rpcError{Code: 1, Message: "invalid params"}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, err := s.lookup(params)
+	if err != nil {
+		return nil, err
+	}
+	deleted := 0
+	for _, del := range delUris {
+		for i, uri := range t.uris {
+			if uri == del {
+				t.uris = append(t.uris[:i], t.uris[i+1:]...)
+				deleted++
+				break
+			}
+		}
+	}
+	t.uris = append(t.uris, addUris...)
+	return []int{deleted, len(addUris)}, nil
+}
+
+// tellByStatus 返回处于指定状态的任务
+func (s *Server) tellByStatus(state string) []aria2.DownloadStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := []aria2.DownloadStatus{}
+	for _, gid := range s.order {
+		if status := s.tasks[gid].status(); status.Status == state {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// tellWaiting 按 offset 和 num 分页返回等待中和已暂停的任务
+func (s *Server) tellWaiting(params []json.RawMessage) (interface{}, *rpcError) {
+	var offset, num int
+	if len(params) < 2 || json.Unmarshal(params[0], &offset) != nil || json.Unmarshal(params[1], &num) != nil ||
+		offset < 0 || num < 0 {
+		return nil, &rpcError{Code: 1, Message: "invalid offset or num"}
+	}
+	waiting := append(s.tellByStatus("waiting"), s.tellByStatus("paused")...)
+	start := min(offset, len(waiting))
+	end := min(offset+num, len(waiting))
+	return waiting[start:end], nil
+}
+
+// update 修改任务状态并返回 GID
+func (s *Server) update(params []json.RawMessage, fn func(t *task)) (interface{}, *rpcError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, err := s.lookup(params)
+	if err != nil {
+		return nil, err
+	}
+	fn(t)
+	return t.gid, nil
+}
+
+// lookup 根据第一个参数查找任务，调用方需持有锁
+func (s *Server) lookup(params []json.RawMessage) (*task, *rpcError) {
+	var gid string
+	if len(params) == 0 || json.Unmarshal(params[0], &gid) != nil {
+		return nil, &rpcError{Code: 1, Message: "invalid gid"}
+	}
+	t, ok := s.tasks[gid]
+	if !ok {
+		return nil, &rpcError{Code: 1, Message: fmt.Sprintf("GID %s is not found", gid)}
+	}
+	return t, nil
+}
@@ -0,0 +1,101 @@
+package aria2test
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// wsGUID 计算 Sec-WebSocket-Accept 使用的固定 GUID
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// isWebSocket 请求是否为 WebSocket 握手
+func isWebSocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// upgrade 完成 WebSocket 握手并记录连接，之后 Notify 会向该连接推送通知
+func (s *Server) upgrade(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	hj, ok := w.(http.Hijacker)
+	if key == "" || !ok {
+		http.Error(w, "无效的 WebSocket 握手", http.StatusBadRequest)
+		return
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return
+	}
+	h := sha1.Sum([]byte(key + wsGUID))
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+		base64.StdEncoding.EncodeToString(h[:]))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return
+	}
+
+	s.mu.Lock()
+	s.conns[conn] = true
+	s.mu.Unlock()
+	go s.discard(conn, rw.Reader)
+}
+
+// discard 丢弃客户端发送的帧，连接断开后移除记录
+func (s *Server) discard(conn net.Conn, r *bufio.Reader) {
+	io.Copy(io.Discard, r)
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+	conn.Close()
+}
+
+// Notify 向所有 WebSocket 连接推送 aria2 通知，例如 Notify("aria2.onDownloadComplete", gid)
+func (s *Server) Notify(method, gid string) {
+	data, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  []map[string]string{{"gid": gid}},
+	})
+	// 服务端发送的帧不加掩码
+	frame := []byte{0x81}
+	switch n := len(data); {
+	case n < 126:
+		frame = append(frame, byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	frame = append(frame, data...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Write(frame)
+	}
+}
+
+// DropConnections 断开所有 WebSocket 连接，用于测试重连
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+		delete(s.conns, conn)
+	}
+}
+
+// Close 断开所有 WebSocket 连接并关闭测试服务器
+func (s *Server) Close() {
+	s.DropConnections()
+	s.Server.Close()
+}
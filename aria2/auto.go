@@ -0,0 +1,59 @@
+package aria2
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AddAuto 根据 input 的类型选择添加任务的方式，统一返回 GID 列表
+//
+//	magnet:?xt=...                 AddMagnet
+//	http、https、ftp、sftp 地址     AddUri（地址指向 .torrent 或 .metalink 时由 aria2 下载后自动处理）
+//	本地 .torrent 文件              读取后 AddTorrent
+//	本地 .metalink、.meta4 文件     读取后 AddMetalink，可能返回多个 GID
+//
+// 本地路径中的 ~ 和环境变量按 WithPathExpansion 的设置展开
+func (a *Aria2) AddAuto(input, dir string) (gids []string, err error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, fmt.Errorf("下载内容不能为空")
+	}
+	single := func(gid string, err error) ([]string, error) {
+		if err != nil {
+			return nil, err
+		}
+		return []string{gid}, nil
+	}
+
+	if u, err := url.Parse(input); err == nil {
+		switch strings.ToLower(u.Scheme) {
+		case "magnet":
+			return single(a.AddMagnet(input, dir))
+		case "http", "https", "ftp", "sftp":
+			return single(a.AddUri(input, dir))
+		}
+	}
+
+	path := input
+	if !a.cfg.literalPaths {
+		path = ExpandPath(path)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".torrent":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("读取种子文件失败: %w", err)
+		}
+		return single(a.AddTorrent(data, dir))
+	case ".metalink", ".meta4":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("读取 Metalink 文件失败: %w", err)
+		}
+		return a.AddMetalink(data, dir)
+	}
+	return nil, fmt.Errorf("无法识别的下载内容: %q", RedactURL(input))
+}
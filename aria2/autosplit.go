@@ -0,0 +1,45 @@
+package aria2
+
+import "strconv"
+
+// autoSplitChunk 自动分段时每个分段对应的文件大小
+const autoSplitChunk = 10 * 1024 * 1024
+
+// WithAutoSplit 按文件大小自动调整分段数：得知文件总大小后通过 changeOption 设置
+// split 为每 10MB 一个分段，最少 1 个，最多不超过单服务器连接数上限。
+// 小文件不再占用 64 个连接，大文件也能用满连接数。
+// 修改正在下载的任务的 split 会让 aria2 重新开始该任务的连接（已下载的数据会保留）
+func WithAutoSplit(enabled bool) Option {
+	return func(a *Aria2) error {
+		a.cfg.autoSplit = enabled
+		return nil
+	}
+}
+
+// autoSplitCount 根据文件大小计算分段数
+func (a *Aria2) autoSplitCount(total int64) int {
+	limit := maxConnectionPerServerLimit
+	if a.cfg.maxTotalConnections > 0 {
+		_, perTask := a.connectionLimits()
+		limit = min(perTask, maxConnectionPerServerLimit)
+	}
+	n := int((total + autoSplitChunk - 1) / autoSplitChunk)
+	return max(1, min(n, limit))
+}
+
+// tuneSplit 第一次得知任务总大小时调整分段数，每个任务只调整一次
+// BT 任务的数据来自多个 peer，split 不起作用，不做调整
+func (a *Aria2) tuneSplit(w *watch, status *DownloadStatus) {
+	if !a.cfg.autoSplit || w.splitTuned || status.Status != "active" || status.InfoHash != "" {
+		return
+	}
+	total := parseLength(status.TotalLength)
+	if total <= 0 {
+		return
+	}
+	w.splitTuned = true
+	split := a.autoSplitCount(total)
+	if err := a.ChangeOption(w.gid, map[string]string{"split": strconv.Itoa(split)}); err != nil {
+		a.logf("调整任务 %s 的分段数失败: %v", w.gid, err)
+	}
+}
@@ -0,0 +1,40 @@
+package aria2_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dxcweb/go-aria2/aria2"
+	"github.com/dxcweb/go-aria2/aria2/aria2test"
+)
+
+func TestAutoSplit(t *testing.T) {
+	tests := []struct {
+		name     string
+		infoHash string
+		want     int
+	}{
+		{name: "HTTP 任务", want: 1},
+		{name: "BT 任务", infoHash: "0123456789abcdef0123456789abcdef01234567", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := func(uris []string, options map[string]interface{}) []aria2.DownloadStatus {
+				return []aria2.DownloadStatus{
+					{Status: "active", TotalLength: "104857600", CompletedLength: "0", InfoHash: tt.infoHash},
+					{Status: "complete", TotalLength: "104857600", CompletedLength: "104857600", InfoHash: tt.infoHash},
+				}
+			}
+			clock := aria2test.NewClock(time.Unix(0, 0))
+			a, srv := attachWithClock(t, script, clock, aria2.WithAutoSplit(true))
+			runClock(t, clock)
+
+			if _, err := a.DownloadWithOptions("http://example.com/file.bin", aria2.DownloadOptions{Dir: t.TempDir()}, nil); err != nil {
+				t.Fatal(err)
+			}
+			if n := count(srv.Calls(), "aria2.changeOption"); n != tt.want {
+				t.Fatalf("changeOption 调用了 %d 次，期望 %d 次", n, tt.want)
+			}
+		})
+	}
+}
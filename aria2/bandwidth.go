@@ -0,0 +1,105 @@
+package aria2
+
+import (
+	"sync"
+	"time"
+)
+
+// bandwidthSamples 最多保留的采样数，按每秒一次采样约为 10 分钟
+const bandwidthSamples = 600
+
+// bandwidthSample 一次全局速度采样
+type bandwidthSample struct {
+	at   time.Time
+	down int64
+	up   int64
+}
+
+// bandwidthStats 全局速度采样的环形缓冲区
+type bandwidthStats struct {
+	mu        sync.Mutex
+	samples   [bandwidthSamples]bandwidthSample
+	next      int   // 下一个写入位置
+	count     int   // 已有采样数
+	totalDown int64 // 本次会话累计下载字节数（按速度积分估算）
+	totalUp   int64 // 本次会话累计上传字节数（按速度积分估算）
+}
+
+// add 记录一次采样并累加会话流量
+func (b *bandwidthStats) add(sample bandwidthSample) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.count > 0 {
+		last := b.samples[(b.next+bandwidthSamples-1)%bandwidthSamples]
+		elapsed := sample.at.Sub(last.at).Seconds()
+		b.totalDown += int64(float64(sample.down) * elapsed)
+		b.totalUp += int64(float64(sample.up) * elapsed)
+	}
+	b.samples[b.next] = sample
+	b.next = (b.next + 1) % bandwidthSamples
+	if b.count < bandwidthSamples {
+		b.count++
+	}
+}
+
+// average 计算 window 时间内的平均速度
+func (b *bandwidthStats) average(window time.Duration, now time.Time) (int64, int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	since := now.Add(-window)
+	var down, up, n int64
+	for i := 1; i <= b.count; i++ {
+		sample := b.samples[(b.next+bandwidthSamples-i)%bandwidthSamples]
+		if sample.at.Before(since) {
+			break
+		}
+		down += sample.down
+		up += sample.up
+		n++
+	}
+	if n == 0 {
+		return 0, 0
+	}
+	return down / n, up / n
+}
+
+// Bandwidth 返回最近 window 时间内的平均下载和上传速度（字节/秒）
+// 采样间隔为 1 秒，最多保留约 10 分钟，超出部分按已有采样计算
+func (a *Aria2) Bandwidth(window time.Duration) (avgDown, avgUp int64) {
+	return a.bandwidth.average(window, a.clock().Now())
+}
+
+// SessionBytes 返回 aria2c 启动以来累计的下载和上传字节数（按采样速度估算）
+func (a *Aria2) SessionBytes() (down, up int64) {
+	a.bandwidth.mu.Lock()
+	defer a.bandwidth.mu.Unlock()
+	return a.bandwidth.totalDown, a.bandwidth.totalUp
+}
+
+// sampleBandwidth 每秒采样一次全局速度，直到服务停止
+func (a *Aria2) sampleBandwidth() {
+	ticker := a.clock().NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			if !a.IsRunning() {
+				return
+			}
+			stat, err := a.GetGlobalStat()
+			if err != nil {
+				continue
+			}
+			a.bandwidth.add(bandwidthSample{
+				at:   a.clock().Now(),
+				down: parseLength(stat.DownloadSpeed),
+				up:   parseLength(stat.UploadSpeed),
+			})
+		case <-a.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package aria2
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+)
+
+// OutputTemplate 根据下载地址和序号（从 0 开始）生成输出文件名，返回空字符串时由 aria2 决定
+type OutputTemplate func(url string, index int) string
+
+// WithOutputTemplate 设置批量下载时的输出文件名模板
+func WithOutputTemplate(template OutputTemplate) Option {
+	return func(a *Aria2) error {
+		a.cfg.outputTemplate = template
+		return nil
+	}
+}
+
+// TemplateFromURL 使用地址路径的最后一段作为文件名
+func TemplateFromURL(rawURL string, index int) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		return ""
+	}
+	return name
+}
+
+// TemplateIndexed 生成带序号的文件名，如 TemplateIndexed("file-", ".zip") 生成 file-001.zip、file-002.zip
+func TemplateIndexed(prefix, ext string) OutputTemplate {
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return func(url string, index int) string {
+		return fmt.Sprintf("%s%03d%s", prefix, index+1, ext)
+	}
+}
+
+// DownloadBatch 批量下载文件，阻塞直到全部结束
+// 返回的结果与 urls 一一对应，单个任务失败不影响其他任务
+// dir 为空且设置了 WithDiskPool 时，每个任务从磁盘池中选择目录，结果的 Dir 为实际使用的目录
+// 文件名重复时按 WithCollisionStrategy 处理，使用 CollisionError 且出现重复时每个结果的 Error 都为 ErrNameCollision
+func (a *Aria2) DownloadBatch(urls []string, dir string, callback DownloadCallback) []DownloadResult {
+	results, _, err := a.DownloadBatchNamed(urls, dir, callback)
+	if err != nil {
+		for i := range results {
+			results[i].Error = err
+		}
+	}
+	return results
+}
+
+// DownloadBatchNamed 与 DownloadBatch 相同，同时返回每个地址对应的最终文件名（url → 文件名）
+// 文件名重复且策略为 CollisionError 时不添加任何任务，返回 ErrNameCollision。
+// 无法预先确定文件名（模板返回空、地址没有路径）的地址映射为空字符串，由 aria2 决定文件名；
+// 同一地址出现多次时映射到第一次分配的文件名
+func (a *Aria2) DownloadBatchNamed(urls []string, dir string, callback DownloadCallback) ([]DownloadResult, map[string]string, error) {
+	results := make([]DownloadResult, len(urls))
+	outs, names, err := a.planBatchNames(urls)
+	if err != nil {
+		return results, nil, err
+	}
+	finalNames := make(map[string]string, len(urls))
+	for i, u := range urls {
+		if _, ok := finalNames[u]; !ok {
+			finalNames[u] = names[i]
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, u := range urls {
+		chosen, err := a.chooseDir(dir)
+		if err != nil {
+			results[i].Error = err
+			continue
+		}
+		results[i].Dir = chosen
+		opts := DownloadOptions{Dir: chosen, Out: outs[i]}
+		w := &watch{callback: callback}
+		w.progress.resumed = hasPartialDownload(chosen, opts.Out)
+		a.recordLocalFile(w, chosen, opts.Out, u)
+		w.timing.QueuedAt = a.clock().Now()
+		w.gid, err = a.AddUriWithOptions([]string{u}, opts)
+		if err != nil {
+			results[i].Error = err
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, w *watch) {
+			defer wg.Done()
+			status, err := a.watchDownload(w)
+			results[i] = w.result(results[i].Dir, status, err)
+		}(i, w)
+	}
+	wg.Wait()
+	return results, finalNames, nil
+}
@@ -0,0 +1,49 @@
+package aria2
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrNoEmbeddedBinary 嵌入的 aria2c 是占位文件
+var ErrNoEmbeddedBinary = errors.New("未嵌入 aria2c 二进制文件，请先运行 scripts/download-aria2.sh 下载各平台的 aria2c 后重新编译")
+
+// HasEmbeddedBinary 当前平台是否嵌入了真正的 aria2c 二进制文件
+// 返回 false 时可以改用系统中安装的 aria2c（配合 Attach）或 WithRemoteBinary
+func HasEmbeddedBinary() bool {
+	return CheckBinaryExists() == nil
+}
+
+// EnsureBinary 检查当前平台是否嵌入了 aria2c，可以在程序初始化时调用以尽早失败
+func EnsureBinary() error {
+	return CheckBinaryExists()
+}
+
+// checkBinaryAvailable 启动前检查是否有可用的 aria2c
+// 嵌入的是占位文件时，只要配置了远程下载或磁盘上已有提取过的文件也可以启动
+func (a *Aria2) checkBinaryAvailable() error {
+	err := EnsureBinary()
+	if err == nil || a.cfg.remoteBinaryURL != "" {
+		return nil
+	}
+	if a.cfg.ephemeralBinary {
+		return err
+	}
+	dir := a.cfg.dataDir
+	if dir == "" {
+		var dirErr error
+		if dir, dirErr = getAppDataDir(); dirErr != nil {
+			return fmt.Errorf("无法获取应用程序数据目录: %w", dirErr)
+		}
+	}
+	filename, nameErr := GetEmbeddedBinaryName()
+	if nameErr != nil {
+		return nameErr
+	}
+	if _, statErr := os.Stat(filepath.Join(dir, filename)); statErr == nil {
+		return nil
+	}
+	return err
+}
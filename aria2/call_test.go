@@ -0,0 +1,37 @@
+package aria2_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dxcweb/go-aria2/aria2"
+)
+
+func TestCallReturnsResult(t *testing.T) {
+	a, _ := attach(t, nil)
+
+	result, err := a.Call("aria2.getVersion", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var version struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(result, &version); err != nil {
+		t.Fatal(err)
+	}
+	if version.Version != "1.37.0" {
+		t.Fatalf("版本为 %q，期望 1.37.0", version.Version)
+	}
+}
+
+func TestCallReturnsRPCError(t *testing.T) {
+	a, _ := attach(t, nil)
+
+	_, err := a.Call("aria2.noSuchMethod", nil)
+	var rpcErr *aria2.RPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("错误为 %v，期望 *aria2.RPCError", err)
+	}
+}
@@ -0,0 +1,85 @@
+package aria2
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCertificateArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		want     string
+		wantWarn bool
+	}{
+		{name: "默认校验证书", want: "--check-certificate=true"},
+		{name: "WithInsecureSkipVerify", opts: []Option{WithInsecureSkipVerify()}, want: "--check-certificate=false", wantWarn: true},
+		{name: "WithCheckCertificate(false)", opts: []Option{WithCheckCertificate(false)}, want: "--check-certificate=false", wantWarn: true},
+		{
+			name: "之后的 WithCheckCertificate(true) 覆盖",
+			opts: []Option{WithInsecureSkipVerify(), WithCheckCertificate(true)},
+			want: "--check-certificate=true",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			args := newInstance(t, append(tt.opts, WithLogger(log.New(&logs, "", 0)))...).buildArgs()
+			if !hasArg(args, tt.want) {
+				t.Errorf("参数中缺少 %s: %v", tt.want, args)
+			}
+			if warned := strings.Contains(logs.String(), "证书校验"); warned != tt.wantWarn {
+				t.Errorf("是否输出警告为 %v，期望 %v，日志: %q", warned, tt.wantWarn, logs.String())
+			}
+		})
+	}
+}
+
+func TestCertificateFileArgs(t *testing.T) {
+	dir := t.TempDir()
+	paths := map[string]string{}
+	for _, name := range []string{"ca.pem", "client.pem", "client.key"} {
+		paths[name] = filepath.Join(dir, name)
+		if err := os.WriteFile(paths[name], nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	args := newInstance(t,
+		WithCACertificate(paths["ca.pem"]),
+		WithClientCert(paths["client.pem"], paths["client.key"]),
+	).buildArgs()
+	for _, want := range []string{
+		"--check-certificate=true",
+		"--ca-certificate=" + paths["ca.pem"],
+		"--certificate=" + paths["client.pem"],
+		"--private-key=" + paths["client.key"],
+	} {
+		if !hasArg(args, want) {
+			t.Errorf("参数中缺少 %s: %v", want, args)
+		}
+	}
+}
+
+func TestCertificateOptionsRejectMissingFiles(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+	existing := filepath.Join(t.TempDir(), "client.pem")
+	if err := os.WriteFile(existing, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, opt := range map[string]Option{
+		"CA 证书不存在":  WithCACertificate(missing),
+		"CA 证书路径为空": WithCACertificate(""),
+		"客户端证书不存在":  WithClientCert(missing, existing),
+		"客户端私钥不存在":  WithClientCert(existing, missing),
+	} {
+		if _, err := NewAria2(opt); err == nil {
+			t.Errorf("%s时 NewAria2 应返回错误", name)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package aria2
+
+import "time"
+
+// Clock 时间来源，轮询、重试退避、带宽采样等定时逻辑都通过它获取时间，
+// 测试时可以替换为 aria2test.Clock 手动推进时间，不需要真正等待
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+	After(d time.Duration) <-chan time.Time
+}
+
+// Ticker 周期触发的定时器，对应 time.Ticker
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// WithClock 设置时间来源，默认使用系统时间
+func WithClock(clock Clock) Option {
+	return func(a *Aria2) error {
+		if clock == nil {
+			clock = realClock{}
+		}
+		a.cfg.clock = clock
+		return nil
+	}
+}
+
+// realClock 使用 time 包的系统时间
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+// realTicker 包装 time.Ticker
+type realTicker struct{ t *time.Ticker }
+
+func (t realTicker) C() <-chan time.Time { return t.t.C }
+func (t realTicker) Stop()               { t.t.Stop() }
+
+// clock 返回实例使用的时间来源
+func (a *Aria2) clock() Clock {
+	if a.cfg.clock == nil {
+		return realClock{}
+	}
+	return a.cfg.clock
+}
@@ -0,0 +1,129 @@
+package aria2_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dxcweb/go-aria2/aria2"
+	"github.com/dxcweb/go-aria2/aria2/aria2test"
+)
+
+// waitUntil 等待 cond 成立，超时则测试失败
+func waitUntil(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("等待%s超时", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWaitReadyProbesOnTicker(t *testing.T) {
+	srv := aria2test.NewServer(nil)
+	defer srv.Close()
+	clock := aria2test.NewClock(time.Unix(0, 0))
+	a, err := aria2.NewAria2(append(srv.Options(), aria2.WithClock(clock))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ready := make(chan error, 1)
+	go func() { ready <- a.WaitReady(context.Background()) }()
+	waitUntil(t, " Ticker 创建", func() bool { return clock.Waiters() == 1 })
+
+	// 第一次探测在 Ticker 第一次触发时进行
+	clock.Advance(99 * time.Millisecond)
+	if n := count(srv.Calls(), "aria2.getVersion"); n != 0 {
+		t.Fatalf("Ticker 触发前调用了 %d 次 getVersion", n)
+	}
+	clock.Advance(time.Millisecond)
+	select {
+	case err := <-ready:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Ticker 触发后 WaitReady 没有返回")
+	}
+}
+
+func TestTellStatusRetryBacksOff(t *testing.T) {
+	const failures = 2
+	var tellStatus atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		var result interface{} = "OK"
+		switch req.Method {
+		case "aria2.getVersion":
+			result = map[string]string{"version": "1.37.0"}
+		case "aria2.getGlobalStat":
+			result = map[string]string{"downloadSpeed": "0", "uploadSpeed": "0"}
+		case "aria2.addUri":
+			result = "2089b05ecca3d829"
+		case "aria2.tellStatus":
+			// 前几次直接断开连接，模拟临时网络错误
+			if tellStatus.Add(1) <= failures {
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+				return
+			}
+			result = aria2.DownloadStatus{GID: "2089b05ecca3d829", Status: "complete", TotalLength: "1", CompletedLength: "1"}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	defer srv.Close()
+
+	clock := aria2test.NewClock(time.Unix(0, 0))
+	a, err := aria2.NewAria2(portOf(srv), aria2.WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := advance(clock)
+	err = a.Attach()
+	stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Stop()
+	// 连接后带宽采样协程会创建一个 Ticker
+	const base = 1
+	waitUntil(t, "带宽采样 Ticker 创建", func() bool { return clock.Waiters() == base })
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := a.DownloadWithOptions("http://example.com/file.bin", aria2.DownloadOptions{Dir: t.TempDir()}, nil)
+		done <- err
+	}()
+
+	// 每次失败后的等待时间翻倍：500ms、1s
+	backoff := 500 * time.Millisecond
+	for i := int32(1); i <= failures; i++ {
+		waitUntil(t, "退避开始", func() bool { return tellStatus.Load() == i && clock.Waiters() == base+1 })
+		clock.Advance(backoff - time.Millisecond)
+		if n := tellStatus.Load(); n != i {
+			t.Fatalf("退避 %v 结束前就重试了", backoff)
+		}
+		clock.Advance(time.Millisecond)
+		backoff *= 2
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("重试成功后下载没有返回")
+	}
+	if n := tellStatus.Load(); n != failures+1 {
+		t.Fatalf("tellStatus 调用了 %d 次，期望 %d 次", n, failures+1)
+	}
+}
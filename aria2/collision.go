@@ -0,0 +1,74 @@
+package aria2
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// CollisionStrategy 批量下载时多个任务的输出文件名相同时的处理方式
+type CollisionStrategy int
+
+const (
+	// CollisionAutoSuffix 为重复的文件名追加 -1、-2 等后缀（默认）
+	CollisionAutoSuffix CollisionStrategy = iota
+	// CollisionError 出现重复文件名时不下载任何文件，返回 ErrNameCollision
+	CollisionError
+)
+
+// ErrNameCollision 批量下载的输出文件名重复
+var ErrNameCollision = errors.New("批量下载的输出文件名重复")
+
+// WithCollisionStrategy 设置批量下载时重复文件名的处理方式
+func WithCollisionStrategy(strategy CollisionStrategy) Option {
+	return func(a *Aria2) error {
+		if strategy != CollisionAutoSuffix && strategy != CollisionError {
+			return fmt.Errorf("无效的文件名冲突处理方式: %d", strategy)
+		}
+		a.cfg.collisionStrategy = strategy
+		return nil
+	}
+}
+
+// planBatchNames 在添加任务前为每个地址确定输出文件名
+// 文件名来自 WithOutputTemplate，未设置时使用地址路径的最后一段；不区分大小写比较，
+// 避免在 Windows、macOS 上互相覆盖。outs 为传给 aria2 的 out 选项（第一次出现的文件名保持原样，
+// 为空时仍由 aria2 决定），names 为每个地址最终的文件名，无法确定时为空字符串
+func (a *Aria2) planBatchNames(urls []string) (outs, names []string, err error) {
+	outs = make([]string, len(urls))
+	names = make([]string, len(urls))
+	used := make(map[string]bool)
+	for i, u := range urls {
+		if a.cfg.outputTemplate != nil {
+			outs[i] = a.sanitizeFilename(a.cfg.outputTemplate(u, i))
+			names[i] = outs[i]
+		} else {
+			names[i] = a.sanitizeFilename(TemplateFromURL(u, i))
+		}
+		if names[i] == "" {
+			continue
+		}
+		if used[strings.ToLower(names[i])] {
+			if a.cfg.collisionStrategy == CollisionError {
+				return nil, nil, fmt.Errorf("%w: %s", ErrNameCollision, names[i])
+			}
+			names[i] = uniqueName(names[i], used)
+			outs[i] = names[i]
+		}
+		used[strings.ToLower(names[i])] = true
+	}
+	return outs, names, nil
+}
+
+// uniqueName 在扩展名前追加 -1、-2 等后缀，直到与 used 中的文件名都不同
+func uniqueName(name string, used map[string]bool) string {
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	for n := 1; ; n++ {
+		candidate := fmt.Sprintf("%s-%d%s", stem, n, ext)
+		if !used[strings.ToLower(candidate)] {
+			return candidate
+		}
+	}
+}
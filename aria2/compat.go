@@ -0,0 +1,221 @@
+package aria2
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// optionSince 本包使用的、由较新版本 aria2 引入的选项及其最低版本
+// 包括 buildArgs 传入的启动参数，以及 SetDiskCache 等通过 changeOption/changeGlobalOption 设置的选项
+var optionSince = map[string]string{
+	"always-resume":                    "1.14.0",
+	"max-resume-failure-tries":         "1.14.0",
+	"disk-cache":                       "1.16.0",
+	"rpc-secure":                       "1.17.0",
+	"rpc-certificate":                  "1.17.0",
+	"rpc-private-key":                  "1.17.0",
+	"rpc-secret":                       "1.18.4",
+	"save-session-interval":            "1.21.0",
+	"optimize-concurrent-downloads":    "1.22.0",
+	"content-disposition-default-utf8": "1.31.0",
+}
+
+// requiredMethods 本包依赖的 RPC 方法，连接的 aria2 不提供时输出警告
+var requiredMethods = []string{
+	"system.multicall",
+	"aria2.changeUri",
+	"aria2.getGlobalStat",
+	"aria2.saveSession",
+}
+
+// compatState 连接的 aria2 的版本和兼容性警告
+type compatState struct {
+	version     string
+	unsupported map[string]bool // 当前版本不支持的选项
+	warnings    []string
+}
+
+// ListMethods 获取 aria2 支持的 RPC 方法名
+func (a *Aria2) ListMethods() ([]string, error) {
+	result, err := a.Call("system.listMethods", nil)
+	if err != nil {
+		return nil, err
+	}
+	var methods []string
+	if err := json.Unmarshal(result, &methods); err != nil {
+		return nil, fmt.Errorf("解析方法列表失败: %w", err)
+	}
+	return methods, nil
+}
+
+// CompatibilityWarnings 返回与连接的 aria2 版本不兼容的情况，包括缺少的 RPC 方法、
+// 当前版本不支持的选项、启动时去掉的启动参数，以及 ChangeOption/ChangeGlobalOption 中被跳过的选项
+func (a *Aria2) CompatibilityWarnings() []string {
+	a.compatMu.Lock()
+	defer a.compatMu.Unlock()
+	return append([]string(nil), a.compat.warnings...)
+}
+
+// checkCompatibility RPC 服务就绪后检查 aria2 的版本和支持的方法，不兼容的地方输出警告
+// 版本过旧的 aria2 不会导致连接失败，只是相关功能不可用
+func (a *Aria2) checkCompatibility() {
+	version, err := a.GetVersion()
+	if err != nil {
+		return
+	}
+	state := compatState{version: version.Version, unsupported: make(map[string]bool)}
+	for option, since := range optionSince {
+		if compareVersion(version.Version, since) < 0 {
+			state.unsupported[option] = true
+			state.warnings = append(state.warnings,
+				fmt.Sprintf("aria2 %s 不支持选项 %s（需要 %s 或更高版本），设置时将跳过", version.Version, option, since))
+		}
+	}
+	// system.listMethods 本身也可能不存在，此时不检查方法
+	if methods, err := a.ListMethods(); err == nil {
+		available := make(map[string]bool, len(methods))
+		for _, method := range methods {
+			available[method] = true
+		}
+		for _, method := range requiredMethods {
+			if !available[method] {
+				state.warnings = append(state.warnings,
+					fmt.Sprintf("aria2 %s 不提供 RPC 方法 %s，相关功能不可用", version.Version, method))
+			}
+		}
+	}
+	sort.Strings(state.warnings)
+	for _, warning := range state.warnings {
+		a.logf("%s", warning)
+	}
+
+	a.compatMu.Lock()
+	// 保留启动前检查启动参数时记录的警告
+	for _, warning := range a.compat.warnings {
+		if !slices.Contains(state.warnings, warning) {
+			state.warnings = append(state.warnings, warning)
+		}
+	}
+	a.compat = state
+	a.compatMu.Unlock()
+}
+
+// supportedArgs 去掉 version 不支持的启动参数，version 为空（无法获取版本）时原样返回
+// 不认识的启动参数会导致 aria2c 启动失败，因此在启动前过滤
+func (a *Aria2) supportedArgs(args []string, version string) []string {
+	if version == "" {
+		return args
+	}
+	filtered := make([]string, 0, len(args))
+	for _, arg := range args {
+		key, _, _ := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if since, ok := optionSince[key]; ok && compareVersion(version, since) < 0 {
+			a.addCompatWarning(fmt.Sprintf("aria2c %s 不支持启动参数 --%s（需要 %s 或更高版本），已去掉", version, key, since))
+			continue
+		}
+		filtered = append(filtered, arg)
+	}
+	return filtered
+}
+
+// binaryVersion 运行 aria2c --version 获取将要启动的 aria2c 的版本，失败时返回空字符串
+func binaryVersion(binaryPath string) string {
+	cmd := exec.Command(binaryPath, "--version")
+	hideWindow(cmd)
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return parseVersionOutput(string(output))
+}
+
+// parseVersionOutput 从 aria2c --version 的输出（第一行为 "aria2 version 1.37.0"）中解析版本号
+func parseVersionOutput(output string) string {
+	line, _, _ := strings.Cut(output, "\n")
+	fields := strings.Fields(line)
+	if len(fields) != 3 || fields[0] != "aria2" || fields[1] != "version" {
+		return ""
+	}
+	return fields[2]
+}
+
+// changeOptions 调用 changeOption 或 changeGlobalOption（gid 为空时），跳过当前版本不支持的选项
+// aria2 不会因为不认识或不能修改的选项返回错误，而是直接忽略，因此只能在调用前按版本过滤
+func (a *Aria2) changeOptions(method, gid string, options map[string]string) error {
+	options = a.supportedOptions(options)
+	if len(options) == 0 {
+		return nil
+	}
+	params := []interface{}{options}
+	if gid != "" {
+		params = []interface{}{gid, options}
+	}
+	_, err := a.Call(method, params)
+	return err
+}
+
+// supportedOptions 去掉当前版本不支持的选项
+func (a *Aria2) supportedOptions(options map[string]string) map[string]string {
+	a.compatMu.Lock()
+	unsupported := a.compat.unsupported
+	a.compatMu.Unlock()
+
+	var skipped []string
+	filtered := make(map[string]string, len(options))
+	for key, value := range options {
+		if unsupported[key] {
+			skipped = append(skipped, key)
+			continue
+		}
+		filtered[key] = value
+	}
+	sort.Strings(skipped)
+	for _, key := range skipped {
+		a.addCompatWarning(fmt.Sprintf("aria2 %s 不支持选项 %s，已跳过", a.compatVersion(), key))
+	}
+	return filtered
+}
+
+// addCompatWarning 记录并输出一条兼容性警告，相同的警告只记录一次
+func (a *Aria2) addCompatWarning(warning string) {
+	a.compatMu.Lock()
+	defer a.compatMu.Unlock()
+	for _, existing := range a.compat.warnings {
+		if existing == warning {
+			return
+		}
+	}
+	a.compat.warnings = append(a.compat.warnings, warning)
+	a.logf("%s", warning)
+}
+
+// compatVersion 返回检查兼容性时获取到的 aria2 版本
+func (a *Aria2) compatVersion() string {
+	a.compatMu.Lock()
+	defer a.compatMu.Unlock()
+	return a.compat.version
+}
+
+// compareVersion 比较两个以点分隔的版本号，a 较旧时返回负数，相同返回 0，较新返回正数
+// 无法解析的部分按 0 处理
+func compareVersion(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var na, nb int
+		if i < len(pa) {
+			na, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			nb, _ = strconv.Atoi(pb[i])
+		}
+		if na != nb {
+			return na - nb
+		}
+	}
+	return 0
+}
@@ -0,0 +1,74 @@
+package aria2_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dxcweb/go-aria2/aria2"
+)
+
+func TestChangeGlobalOptionSkipsUnsupported(t *testing.T) {
+	var calls []map[string]string
+	opts := newRPCServer(t, func(req rpcRequest) (interface{}, error) {
+		switch req.Method {
+		case "aria2.getVersion":
+			return map[string]string{"version": "1.15.2"}, nil
+		case "aria2.changeGlobalOption":
+			var options map[string]string
+			json.Unmarshal(req.Params[0], &options)
+			calls = append(calls, options)
+		}
+		return "OK", nil
+	})
+	a, err := aria2.NewAria2(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Attach(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { a.Stop() })
+
+	if err := a.ChangeGlobalOption(map[string]string{"disk-cache": "0", "max-overall-download-limit": "1M"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 || len(calls[0]) != 1 || calls[0]["max-overall-download-limit"] != "1M" {
+		t.Fatalf("调用记录为 %v，期望只设置 max-overall-download-limit", calls)
+	}
+	if err := a.SetDiskCache(0); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("只有不支持的选项时调用了 changeGlobalOption: %v", calls)
+	}
+	var warned bool
+	for _, warning := range a.CompatibilityWarnings() {
+		warned = warned || strings.Contains(warning, "disk-cache")
+	}
+	if !warned {
+		t.Fatalf("兼容性警告 %v 中没有 disk-cache", a.CompatibilityWarnings())
+	}
+}
+
+func TestChangeOptionReturnsMissingGID(t *testing.T) {
+	var calls int
+	opts := newRPCServer(t, func(req rpcRequest) (interface{}, error) {
+		calls++
+		return nil, &aria2.RPCError{Code: 1, Message: "GID 2089b05ecca3d829 is not found"}
+	})
+	a, err := aria2.NewAria2(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = a.ChangeOption("2089b05ecca3d829", map[string]string{"split": "4", "max-download-limit": "1M"})
+	var rpcErr *aria2.RPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("错误为 %v，期望任务不存在的错误", err)
+	}
+	if calls != 1 {
+		t.Fatalf("任务不存在时调用了 %d 次，不应逐个重试", calls)
+	}
+}
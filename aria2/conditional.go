@@ -0,0 +1,61 @@
+package aria2
+
+import (
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// WithConditionalGet 只在远程文件比本地文件新时才下载，对应 --conditional-get（同时设置 --allow-overwrite）
+// 依赖本地已有的文件（文件名取 Out 或地址中的文件名）和服务器的 Last-Modified 支持，只对 HTTP(S) 有效；
+// 存在 .aria2 控制文件时 aria2 会忽略这个选项。未下载新内容时结果的 NotModified 为 true
+func WithConditionalGet(enabled bool) Option {
+	return func(a *Aria2) error {
+		a.cfg.conditionalGet = enabled
+		return nil
+	}
+}
+
+// conditionalArgs 返回条件下载相关的启动参数
+func (a *Aria2) conditionalArgs() []string {
+	if !a.cfg.conditionalGet {
+		return nil
+	}
+	return []string{"--conditional-get=true", "--allow-overwrite=true"}
+}
+
+// recordLocalFile 启用条件下载时记录下载前本地文件的状态，用于判断下载后文件是否被更新
+func (a *Aria2) recordLocalFile(w *watch, dir, out, rawURL string) {
+	if !a.cfg.conditionalGet {
+		return
+	}
+	if out == "" {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return
+		}
+		out = path.Base(u.Path)
+		if out == "/" || out == "." {
+			return
+		}
+	}
+	info, err := os.Stat(filepath.Join(dir, out))
+	if err != nil {
+		return
+	}
+	w.localPath = filepath.Join(dir, out)
+	w.localBefore = info
+}
+
+// notModified 下载完成后本地文件的修改时间和大小都没有变化，说明服务器返回了 304
+func (w *watch) notModified(status *DownloadStatus) bool {
+	if w.localBefore == nil || status == nil || status.Status != "complete" {
+		return false
+	}
+	info, err := os.Stat(w.localPath)
+	if err != nil {
+		return false
+	}
+	return info.ModTime().Equal(w.localBefore.ModTime()) && info.Size() == w.localBefore.Size()
+}
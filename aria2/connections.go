@@ -0,0 +1,104 @@
+package aria2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// aria2 相关选项的默认值和上限
+const (
+	defaultMaxConcurrentDownloads = 5  // aria2 默认的 max-concurrent-downloads
+	maxConnectionPerServerLimit   = 16 // aria2 允许的 max-connection-per-server 最大值
+)
+
+// WithMaxTotalConnections 限制所有任务的连接总数，避免耗尽文件描述符
+// aria2 没有全局连接数上限，这里按 同时下载任务数 × 单任务连接数 ≤ n 近似：
+// 同时下载任务数不超过 n，单任务的 split、max-connection-per-server 和 bt-max-peers（未通过 WithMaxPeers 设置时）
+// 取 n / 同时下载任务数，添加任务时通过选项指定的更大的值（bt-max-peers 为 0 表示不限制）也会被限制为该值。
+// 添加任务时按当前的同时下载任务数重新计算，
+// 已在运行的任务不受影响，因此修改同时下载任务数后的一段时间内总数可能短暂超过 n
+func WithMaxTotalConnections(n int) Option {
+	return func(a *Aria2) error {
+		if n <= 0 {
+			return fmt.Errorf("最大连接总数必须大于0: %d", n)
+		}
+		a.cfg.maxTotalConnections = n
+		return nil
+	}
+}
+
+// connectionLimits 根据连接总数计算同时下载任务数和单任务连接数
+func (a *Aria2) connectionLimits() (concurrent, perTask int) {
+	n := a.cfg.maxTotalConnections
+	a.mu.Lock()
+	concurrent = a.concurrent
+	a.mu.Unlock()
+	if concurrent <= 0 {
+		concurrent = a.cfg.maxConcurrentDownloads
+	}
+	if concurrent <= 0 {
+		concurrent = defaultMaxConcurrentDownloads
+	}
+	if concurrent > n {
+		concurrent = n
+	}
+	return concurrent, n / concurrent
+}
+
+// connectionArgs 在启动参数中应用连接总数限制
+func (a *Aria2) connectionArgs(args []string) []string {
+	if a.cfg.maxTotalConnections <= 0 {
+		return args
+	}
+	concurrent, perTask := a.connectionLimits()
+	args = setArg(args, "max-concurrent-downloads", strconv.Itoa(concurrent))
+	args = setArg(args, "split", strconv.Itoa(perTask))
+	args = setArg(args, "max-connection-per-server", strconv.Itoa(min(perTask, maxConnectionPerServerLimit)))
+	if a.cfg.btMaxPeers < 0 {
+		args = setArg(args, "bt-max-peers", strconv.Itoa(perTask))
+	}
+	return args
+}
+
+// connectionOptions 在新任务的选项中应用连接总数限制
+// 未指定的选项设为上限，已指定的选项不超过上限时保留
+func (a *Aria2) connectionOptions(options map[string]interface{}) map[string]interface{} {
+	if a.cfg.maxTotalConnections <= 0 {
+		return options
+	}
+	if options == nil {
+		options = map[string]interface{}{}
+	}
+	_, perTask := a.connectionLimits()
+	limits := map[string]int{
+		"split":                     perTask,
+		"max-connection-per-server": min(perTask, maxConnectionPerServerLimit),
+	}
+	if a.cfg.btMaxPeers < 0 {
+		limits["bt-max-peers"] = perTask
+	}
+	for key, limit := range limits {
+		if value, ok := options[key]; ok {
+			// 无法解析的值交给 aria2 报错；0（bt-max-peers 为 0 表示不限制）和负数同样改为上限
+			n, err := strconv.Atoi(fmt.Sprint(value))
+			if err != nil || (n > 0 && n <= limit) {
+				continue
+			}
+		}
+		options[key] = strconv.Itoa(limit)
+	}
+	return options
+}
+
+// setArg 替换参数列表中已有的 --name=value，不存在时追加
+func setArg(args []string, name, value string) []string {
+	prefix := "--" + name + "="
+	for i, arg := range args {
+		if strings.HasPrefix(arg, prefix) {
+			args[i] = prefix + value
+			return args
+		}
+	}
+	return append(args, prefix+value)
+}
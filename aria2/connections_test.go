@@ -0,0 +1,54 @@
+package aria2
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConnectionOptions(t *testing.T) {
+	// 连接总数 10，同时下载 2 个任务，每个任务最多 5 个连接
+	opts := []Option{WithMaxTotalConnections(10), WithMaxConcurrentDownloads(2)}
+	tests := []struct {
+		name    string
+		opts    []Option
+		options map[string]interface{}
+		want    map[string]interface{}
+	}{
+		{
+			name: "未指定时使用上限",
+			opts: opts,
+			want: map[string]interface{}{"split": "5", "max-connection-per-server": "5", "bt-max-peers": "5"},
+		},
+		{
+			name:    "不超过上限的值保留",
+			opts:    opts,
+			options: map[string]interface{}{"split": "1", "max-connection-per-server": "2", "bt-max-peers": "3"},
+			want:    map[string]interface{}{"split": "1", "max-connection-per-server": "2", "bt-max-peers": "3"},
+		},
+		{
+			name:    "超过上限的值被限制",
+			opts:    opts,
+			options: map[string]interface{}{"split": "16", "max-connection-per-server": "16", "bt-max-peers": "0"},
+			want:    map[string]interface{}{"split": "5", "max-connection-per-server": "5", "bt-max-peers": "5"},
+		},
+		{
+			name:    "WithMaxPeers 设置后不修改 bt-max-peers",
+			opts:    append(opts, WithMaxPeers(50)),
+			options: map[string]interface{}{"bt-max-peers": "0"},
+			want:    map[string]interface{}{"split": "5", "max-connection-per-server": "5", "bt-max-peers": "0"},
+		},
+		{
+			name:    "未设置连接总数时不修改",
+			options: map[string]interface{}{"split": "16"},
+			want:    map[string]interface{}{"split": "16"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newInstance(t, tt.opts...).connectionOptions(tt.options)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("选项为 %v，期望 %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package aria2
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isConnRefusedOrReset 是否为连接被拒绝或连接被重置
+func isConnRefusedOrReset(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package aria2
+
+import (
+	"errors"
+	"syscall"
+)
+
+// wsaeconnrefused Windows 上连接被拒绝的错误码，syscall 包没有定义
+const wsaeconnrefused = syscall.Errno(10061)
+
+// isConnRefusedOrReset 是否为连接被拒绝或连接被重置
+// Windows 上套接字错误是 WSA 错误码，不会匹配 syscall.ECONNREFUSED
+func isConnRefusedOrReset(err error) bool {
+	return errors.Is(err, wsaeconnrefused) || errors.Is(err, syscall.WSAECONNRESET)
+}
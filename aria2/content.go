@@ -0,0 +1,116 @@
+package aria2
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ErrUnexpectedContent 下载完成的文件大小或内容类型不符合预期，通常是服务器返回了错误页面
+var ErrUnexpectedContent = errors.New("下载的内容不符合预期")
+
+// WithMinFileSize 下载完成后检查文件大小，小于 bytes 时返回 ErrUnexpectedContent
+// 用于发现服务器以 200 返回错误页面或空文件的情况
+func WithMinFileSize(bytes int64) Option {
+	return func(a *Aria2) error {
+		if bytes < 0 {
+			return fmt.Errorf("最小文件大小不能为负数: %d", bytes)
+		}
+		a.cfg.minFileSize = bytes
+		return nil
+	}
+}
+
+// WithExpectedContentType 下载完成后根据文件头判断内容类型，不在 types 中时返回 ErrUnexpectedContent
+// types 为 MIME 类型，支持 "video/*" 形式的通配；判断使用 http.DetectContentType，
+// 无法识别的二进制内容为 "application/octet-stream"
+func WithExpectedContentType(types ...string) Option {
+	return func(a *Aria2) error {
+		if len(types) == 0 {
+			return fmt.Errorf("内容类型列表不能为空")
+		}
+		a.cfg.expectedContentTypes = append([]string(nil), types...)
+		return nil
+	}
+}
+
+// WithDeleteUnexpectedContent 文件不符合 WithMinFileSize 或 WithExpectedContentType 时删除该文件
+func WithDeleteUnexpectedContent() Option {
+	return func(a *Aria2) error {
+		a.cfg.deleteUnexpected = true
+		return nil
+	}
+}
+
+// checkContent 检查下载完成的文件，未设置检查条件时直接返回
+func (a *Aria2) checkContent(status *DownloadStatus) error {
+	if a.cfg.minFileSize <= 0 && len(a.cfg.expectedContentTypes) == 0 {
+		return nil
+	}
+	for _, file := range status.Files {
+		// BT 任务中未选择下载的文件不会写入磁盘，不需要检查
+		if file.Path == "" || file.Selected != "true" {
+			continue
+		}
+		err := a.checkFileContent(file.Path)
+		if err == nil {
+			continue
+		}
+		if errors.Is(err, ErrUnexpectedContent) && a.cfg.deleteUnexpected {
+			if removeErr := os.Remove(file.Path); removeErr != nil && !os.IsNotExist(removeErr) {
+				a.logf("删除不符合预期的文件失败: %v", removeErr)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// checkFileContent 检查单个文件的大小和内容类型
+func (a *Aria2) checkFileContent(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("无法检查下载的文件: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("无法检查下载的文件: %w", err)
+	}
+	if info.Size() < a.cfg.minFileSize {
+		return fmt.Errorf("%w: %s 大小为 %d 字节，小于 %d 字节", ErrUnexpectedContent, path, info.Size(), a.cfg.minFileSize)
+	}
+
+	if len(a.cfg.expectedContentTypes) == 0 {
+		return nil
+	}
+	header := make([]byte, 512)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return fmt.Errorf("无法检查下载的文件: %w", err)
+	}
+	detected, _, _ := mime.ParseMediaType(http.DetectContentType(header[:n]))
+	for _, expected := range a.cfg.expectedContentTypes {
+		if matchContentType(expected, detected) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s 的内容类型为 %s", ErrUnexpectedContent, path, detected)
+}
+
+// matchContentType 判断内容类型是否匹配，expected 支持 "type/*"
+func matchContentType(expected, detected string) bool {
+	expected = strings.ToLower(strings.TrimSpace(expected))
+	if mediaType, _, err := mime.ParseMediaType(expected); err == nil {
+		expected = mediaType
+	}
+	if prefix, ok := strings.CutSuffix(expected, "/*"); ok {
+		return strings.HasPrefix(detected, prefix+"/")
+	}
+	return expected == detected
+}
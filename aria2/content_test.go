@@ -0,0 +1,31 @@
+package aria2
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckContentSkipsUnselectedFiles(t *testing.T) {
+	dir := t.TempDir()
+	small := filepath.Join(dir, "small.txt")
+	if err := os.WriteFile(small, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a := newInstance(t, WithMinFileSize(1024))
+
+	// 未选择的文件即使不存在或过小也不检查
+	status := &DownloadStatus{Files: []File{
+		{Path: filepath.Join(dir, "missing.bin"), Selected: "false"},
+		{Path: small, Selected: "false"},
+	}}
+	if err := a.checkContent(status); err != nil {
+		t.Fatalf("未选择的文件不应检查: %v", err)
+	}
+
+	status.Files[1].Selected = "true"
+	if err := a.checkContent(status); !errors.Is(err, ErrUnexpectedContent) {
+		t.Fatalf("错误为 %v，期望 ErrUnexpectedContent", err)
+	}
+}
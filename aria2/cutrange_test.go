@@ -0,0 +1,54 @@
+package aria2
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCutRange(t *testing.T) {
+	const content = "0123456789"
+	tests := []struct {
+		name     string
+		start, n int64
+		want     string
+	}{
+		{name: "从头截取", start: 0, n: 4, want: "0123"},
+		{name: "从中间截取", start: 3, n: 4, want: "3456"},
+		{name: "从头截取且文件较短", start: 0, n: 20, want: content},
+		{name: "从中间截取到末尾之后", start: 7, n: 10, want: "789"},
+		{name: "起点超出文件末尾", start: 20, n: 5, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "file.bin")
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := cutRange(path, tt.start, tt.n); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("截取后的内容为 %q，期望 %q", got, tt.want)
+			}
+			// 临时文件已经替换原文件
+			if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+				t.Fatalf("目录中留下了 %d 个文件", len(entries))
+			}
+		})
+	}
+}
+
+func TestCutRangeMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.bin")
+	for _, start := range []int64{0, 5} {
+		if err := cutRange(path, start, 4); err == nil {
+			t.Errorf("文件不存在时 cutRange(start=%d) 应返回错误", start)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package aria2
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WithDefaultDir 设置默认下载目录，添加任务时 dir 为空则使用该目录
+// 未设置时使用系统的“下载”目录
+func WithDefaultDir(path string) Option {
+	return func(a *Aria2) error {
+		if path == "" {
+			return fmt.Errorf("默认下载目录不能为空")
+		}
+		a.cfg.defaultDir = path
+		return nil
+	}
+}
+
+// ResolveDir 返回任务实际使用的下载目录（绝对路径）
+// dir 为空时使用默认下载目录，未关闭 WithPathExpansion 时先展开其中的 ~ 和环境变量
+func (a *Aria2) ResolveDir(dir string) (string, error) {
+	if dir == "" {
+		dir = a.cfg.defaultDir
+	}
+	if dir == "" {
+		var err error
+		dir, err = defaultDownloadDir()
+		if err != nil {
+			return "", fmt.Errorf("无法获取默认下载目录: %w", err)
+		}
+	}
+	if !a.cfg.literalPaths {
+		dir = ExpandPath(dir)
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("解析下载目录失败: %w", err)
+	}
+	return abs, nil
+}
+
+// defaultDownloadDir 获取系统的“下载”目录
+func defaultDownloadDir() (string, error) {
+	// Linux 桌面环境优先使用 XDG_DOWNLOAD_DIR
+	if dir := os.Getenv("XDG_DOWNLOAD_DIR"); dir != "" {
+		return dir, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, "Downloads"), nil
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package aria2
+
+import "syscall"
+
+// freeSpace 返回 dir 所在磁盘当前用户可用的剩余空间（字节）
+func freeSpace(dir string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0, err
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), nil
+}
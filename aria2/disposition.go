@@ -0,0 +1,20 @@
+package aria2
+
+// UseContentDisposition 返回 DownloadOptions.UseContentDisposition 使用的 *bool
+func UseContentDisposition(use bool) *bool {
+	return &use
+}
+
+// outputName 确定任务的 out 选项
+// aria2 只在没有指定 out 时才使用 Content-Disposition 中的文件名，因此设置了 Out 时直接使用；
+// 不使用 Content-Disposition 时用地址路径的最后一段作为 out，地址中没有文件名时仍交给 aria2 决定
+func (a *Aria2) outputName(uri string, opts DownloadOptions) string {
+	if opts.Out != "" || opts.UseContentDisposition == nil || *opts.UseContentDisposition {
+		return opts.Out
+	}
+	name := TemplateFromURL(uri, 0)
+	if name == "" {
+		return ""
+	}
+	return a.sanitizeFilename(name)
+}
@@ -0,0 +1,50 @@
+package aria2
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// WithAsyncDNS 设置是否使用 aria2 内置的异步 DNS 解析，对应 --async-dns（aria2 默认开启）
+// WithDNSServers 指定的服务器只在异步 DNS 开启时生效
+func WithAsyncDNS(enabled bool) Option {
+	return func(a *Aria2) error {
+		a.cfg.asyncDNS = &enabled
+		return nil
+	}
+}
+
+// WithDNSServers 设置下载时使用的 DNS 服务器，对应 --async-dns-server，用于绕过无法正常解析的本地 DNS
+// servers 为 IP 地址（如 "8.8.8.8"、"2606:4700:4700::1111"），不支持域名和端口。
+// 只在异步 DNS 开启时生效，不要同时使用 WithAsyncDNS(false)
+func WithDNSServers(servers []string) Option {
+	return func(a *Aria2) error {
+		if len(servers) == 0 {
+			return fmt.Errorf("DNS 服务器列表不能为空")
+		}
+		for _, server := range servers {
+			if net.ParseIP(server) == nil {
+				return fmt.Errorf("无效的 DNS 服务器地址: %q", server)
+			}
+		}
+		a.cfg.dnsServers = append([]string(nil), servers...)
+		return nil
+	}
+}
+
+// dnsArgs 返回 DNS 相关的启动参数
+func (a *Aria2) dnsArgs() []string {
+	var args []string
+	if a.cfg.asyncDNS != nil {
+		args = append(args, "--async-dns="+strconv.FormatBool(*a.cfg.asyncDNS))
+	}
+	if len(a.cfg.dnsServers) > 0 {
+		if a.cfg.asyncDNS != nil && !*a.cfg.asyncDNS {
+			a.logf("异步 DNS 已关闭，WithDNSServers 设置的 DNS 服务器不会生效")
+		}
+		args = append(args, "--async-dns-server="+strings.Join(a.cfg.dnsServers, ","))
+	}
+	return args
+}
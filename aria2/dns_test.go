@@ -0,0 +1,49 @@
+package aria2
+
+import (
+	"io"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestDNSArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{name: "默认", want: nil},
+		{name: "关闭异步 DNS", opts: []Option{WithAsyncDNS(false)}, want: []string{"--async-dns=false"}},
+		{
+			name: "指定 DNS 服务器",
+			opts: []Option{WithAsyncDNS(true), WithDNSServers([]string{"8.8.8.8", "2606:4700:4700::1111"})},
+			want: []string{"--async-dns=true", "--async-dns-server=8.8.8.8,2606:4700:4700::1111"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := newInstance(t, append(tt.opts, WithLogger(log.New(io.Discard, "", 0)))...).buildArgs()
+			for _, arg := range tt.want {
+				if !hasArg(args, arg) {
+					t.Errorf("参数中缺少 %s: %v", arg, args)
+				}
+			}
+			if tt.want == nil {
+				for _, arg := range args {
+					if strings.HasPrefix(arg, "--async-dns") {
+						t.Errorf("默认不应设置 %s", arg)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWithDNSServersRejectsInvalid(t *testing.T) {
+	for _, servers := range [][]string{nil, {"dns.google"}, {"8.8.8.8:53"}} {
+		if _, err := NewAria2(WithDNSServers(servers)); err == nil {
+			t.Errorf("WithDNSServers(%q) 应返回错误", servers)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package aria2
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ResumeFailurePolicy 断点续传失败时的处理策略
+type ResumeFailurePolicy int
+
+const (
+	// FailOnResumeError 直接返回错误（默认）
+	FailOnResumeError ResumeFailurePolicy = iota
+	// RestartFromScratch 删除过期的 .aria2 控制文件和已下载的部分，重新下载一次
+	RestartFromScratch
+)
+
+// errCodeCannotResume aria2 错误代码 8：需要断点续传但无法续传
+const errCodeCannotResume = "8"
+
+// DownloadWithOptions 使用指定选项下载文件，阻塞直到下载完成或出错，返回文件路径
+func (a *Aria2) DownloadWithOptions(url string, opts DownloadOptions, callback DownloadCallback) (string, error) {
+	return a.download([]string{url}, opts, callback)
+}
+
+// DownloadAsync 添加下载任务后立即返回，不阻塞
+// 任务结束（完成、出错或被移除）时 done 会收到且只收到一个结果，之后被关闭
+func (a *Aria2) DownloadAsync(url, dir, out string) (gid string, done <-chan DownloadResult, err error) {
+	w, dir, err := a.addWatched([]string{url}, DownloadOptions{Dir: dir, Out: out}, a.clock().Now(), nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	ch := make(chan DownloadResult, 1)
+	go func() {
+		defer close(ch)
+		status, err := a.watchDownload(w)
+		ch <- w.result(dir, status, err)
+	}()
+	return w.gid, ch, nil
+}
+
+// DownloadDetailed 与 DownloadWithOptions 相同，但返回包含最终状态和耗时信息的完整结果
+func (a *Aria2) DownloadDetailed(url string, opts DownloadOptions, callback DownloadCallback) DownloadResult {
+	return a.downloadResult([]string{url}, opts, callback)
+}
+
+// download 添加任务并监控直到结束，返回文件路径
+func (a *Aria2) download(uris []string, opts DownloadOptions, callback DownloadCallback) (string, error) {
+	result := a.downloadResult(uris, opts, callback)
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Status.path(), nil
+}
+
+// downloadResult 添加任务并监控直到结束
+// 断点续传失败且策略为 RestartFromScratch 时，清理残留文件后重新下载一次，
+// 耗时信息从第一次提交任务开始计算
+func (a *Aria2) downloadResult(uris []string, opts DownloadOptions, callback DownloadCallback) DownloadResult {
+	queuedAt := a.clock().Now()
+	restarted := false
+	if len(uris) > 0 {
+		opts.Out = a.outputName(uris[0], opts)
+	}
+	for {
+		w, dir, err := a.addWatched(uris, opts, queuedAt, callback)
+		if err != nil {
+			return DownloadResult{Dir: dir, Error: err}
+		}
+		status, err := a.watchDownload(w)
+
+		var dlErr *DownloadError
+		if !restarted && a.cfg.resumeFailurePolicy == RestartFromScratch &&
+			errors.As(err, &dlErr) && dlErr.Code == errCodeCannotResume {
+			if removeErr := removePartialDownload(dlErr.Status); removeErr == nil {
+				a.RemoveDownloadResult(w.gid)
+				restarted = true
+				continue
+			}
+		}
+		return w.result(dir, status, err)
+	}
+}
+
+// addWatched 添加任务并返回监控它所需的 watch 和实际使用的下载目录
+// 添加前检测已有的部分文件（断点续传）并记录条件下载的本地文件，queuedAt 为提交任务的时间；
+// 解析目录失败时返回的目录为空
+func (a *Aria2) addWatched(uris []string, opts DownloadOptions, queuedAt time.Time, callback DownloadCallback) (*watch, string, error) {
+	dir, err := a.ResolveDir(opts.Dir)
+	if err != nil {
+		return nil, "", err
+	}
+	w := &watch{callback: callback}
+	w.progress.resumed = hasPartialDownload(dir, opts.Out)
+	w.timing.QueuedAt = queuedAt
+	a.recordLocalFile(w, dir, opts.Out, uris[0])
+	w.gid, err = a.AddUriWithOptions(uris, opts)
+	if err != nil {
+		return nil, dir, err
+	}
+	return w, dir, nil
+}
+
+// removePartialDownload 删除未完成的文件及其 .aria2 控制文件
+func removePartialDownload(status *DownloadStatus) error {
+	path := status.path()
+	if path == "" {
+		return fmt.Errorf("无法获取任务的文件路径: %s", status.GID)
+	}
+	for _, name := range []string{path + ".aria2", path} {
+		if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除残留文件失败: %w", err)
+		}
+	}
+	return nil
+}
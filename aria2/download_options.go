@@ -0,0 +1,121 @@
+package aria2
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DownloadOptions 单个下载任务的选项
+type DownloadOptions struct {
+	Dir    string // 下载目录，为空时使用默认下载目录
+	Out    string // 输出文件名，设置后总是使用该文件名，不会被服务器的 Content-Disposition 覆盖
+	Paused bool   // 添加后保持暂停状态，需要调用 Unpause 才开始下载
+
+	// Referer 固定的 Referer 请求头，所有请求（包括重定向后）都发送这个值
+	Referer string
+	// UseHostHeaderAsReferer 使用当前请求的地址作为 Referer（aria2 的 referer=*），
+	// 适用于要求 Referer 与下载地址同源的防盗链服务器，不能与 Referer 同时设置
+	UseHostHeaderAsReferer bool
+
+	// Metadata 任务的自定义元数据（如业务对象的 ID），只保存在本进程中，
+	// 可通过 Aria2.Metadata、DownloadResult 和 Manager 的 Task 获取，任务结果被清除时删除
+	Metadata map[string]string
+
+	// MaxTries 最大尝试次数，对应 max-tries，0 表示使用全局设置（WithMaxTries）
+	MaxTries int
+	// RetryWait 重试间隔，对应 retry-wait（按秒向上取整），0 表示使用全局设置（WithRetryWait）
+	RetryWait time.Duration
+
+	// PieceSelector 分片选择算法，对应 stream-piece-selector，可选值见 WithPieceSelector，为空时使用全局设置
+	PieceSelector string
+
+	// UseContentDisposition 未设置 Out 时是否使用服务器 Content-Disposition 中的文件名，nil 表示使用（aria2 默认行为），
+	// 设为 false 时使用地址路径的最后一段作为文件名，见 UseContentDisposition 函数
+	UseContentDisposition *bool
+}
+
+// toMap 转换为 aria2.addUri 的选项参数
+func (o *DownloadOptions) toMap() map[string]interface{} {
+	options := map[string]interface{}{}
+	if o.Dir != "" {
+		options["dir"] = o.Dir
+	}
+	if o.Out != "" {
+		options["out"] = o.Out
+	}
+	if o.Paused {
+		options["pause"] = "true"
+	}
+	if o.UseHostHeaderAsReferer {
+		options["referer"] = "*"
+	} else if o.Referer != "" {
+		options["referer"] = o.Referer
+	}
+	if o.MaxTries > 0 {
+		options["max-tries"] = strconv.Itoa(o.MaxTries)
+	}
+	if o.RetryWait > 0 {
+		options["retry-wait"] = retrySeconds(o.RetryWait)
+	}
+	if o.PieceSelector != "" {
+		options["stream-piece-selector"] = o.PieceSelector
+	}
+	return options
+}
+
+// AddUriWithOptions 使用指定选项添加下载任务，uris 中的所有地址作为同一文件的镜像
+func (a *Aria2) AddUriWithOptions(uris []string, opts DownloadOptions) (string, error) {
+	if len(uris) == 0 {
+		return "", fmt.Errorf("下载地址列表不能为空")
+	}
+	for _, uri := range uris {
+		if err := validateURI(uri); err != nil {
+			return "", err
+		}
+	}
+	if opts.UseHostHeaderAsReferer && opts.Referer != "" {
+		return "", fmt.Errorf("Referer 与 UseHostHeaderAsReferer 不能同时设置")
+	}
+	if err := opts.validateRetry(); err != nil {
+		return "", err
+	}
+	if opts.PieceSelector != "" {
+		if err := validPieceSelector(opts.PieceSelector); err != nil {
+			return "", err
+		}
+	}
+	opts.Out = a.outputName(uris[0], opts)
+	dir, err := a.ResolveDir(opts.Dir)
+	if err != nil {
+		return "", err
+	}
+	opts.Dir = dir
+	gid, err := a.addUri(uris, opts.toMap())
+	if err != nil {
+		return "", err
+	}
+	a.setMetadata(gid, opts.Metadata)
+	return gid, nil
+}
+
+// AddUriPaused 添加一个处于暂停状态的下载任务，调用 Unpause 后才开始下载
+func (a *Aria2) AddUriPaused(uri, dir, out string) (string, error) {
+	gid, err := a.AddUriWithOptions([]string{uri}, DownloadOptions{
+		Dir:    dir,
+		Out:    out,
+		Paused: true,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	status, err := a.TellStatus(gid)
+	if err != nil {
+		return gid, err
+	}
+	if status.Status != "paused" {
+		return gid, fmt.Errorf("任务未处于暂停状态: %s", status.Status)
+	}
+	return gid, nil
+}
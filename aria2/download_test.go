@@ -0,0 +1,123 @@
+package aria2_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dxcweb/go-aria2/aria2"
+	"github.com/dxcweb/go-aria2/aria2/aria2test"
+)
+
+func TestDownloadAlreadyCompleteOnFirstStatus(t *testing.T) {
+	complete := func(uris []string, options map[string]interface{}) []aria2.DownloadStatus {
+		dir, _ := options["dir"].(string)
+		return []aria2.DownloadStatus{{
+			Status:          "complete",
+			TotalLength:     "1024",
+			CompletedLength: "1024",
+			Files:           []aria2.File{{Path: dir + "/done.bin", Selected: "true"}},
+		}}
+	}
+	// 时间不会前进，如果需要等待轮询就会一直阻塞
+	clock := aria2test.NewClock(time.Unix(0, 0))
+	a, srv := attachWithClock(t, complete, clock)
+
+	type result struct {
+		path string
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		path, err := a.DownloadWithOptions("http://example.com/done.bin", aria2.DownloadOptions{Dir: t.TempDir()}, nil)
+		done <- result{path, err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			t.Fatal(r.err)
+		}
+		if r.path == "" {
+			t.Fatal("没有返回文件路径")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("任务第一次查询时已完成，但下载没有立即返回")
+	}
+	if n := count(srv.Calls(), "aria2.tellStatus"); n != 1 {
+		t.Fatalf("tellStatus 调用了 %d 次，期望 1 次", n)
+	}
+}
+
+func TestDownloadReportsProgressUntilComplete(t *testing.T) {
+	clock := aria2test.NewClock(time.Unix(0, 0))
+	a, srv := attachWithClock(t, nil, clock)
+	runClock(t, clock)
+
+	var statuses []string
+	path, err := a.DownloadWithOptions("http://example.com/file.bin", aria2.DownloadOptions{Dir: t.TempDir()},
+		func(status *aria2.DownloadStatus) {
+			statuses = append(statuses, status.Status)
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != "file.bin" {
+		t.Fatalf("文件路径为 %q，期望以 file.bin 结尾", path)
+	}
+	if len(statuses) == 0 || statuses[0] != "active" {
+		t.Fatalf("回调收到的状态为 %v，期望先收到 active", statuses)
+	}
+	if n := count(srv.Calls(), "aria2.tellStatus"); n < 2 {
+		t.Fatalf("tellStatus 调用了 %d 次，期望至少 2 次", n)
+	}
+}
+
+func TestConcurrentInstancesDownload(t *testing.T) {
+	const n = 2
+	clock := aria2test.NewClock(time.Unix(0, 0))
+	runClock(t, clock)
+
+	var wg sync.WaitGroup
+	servers := make([]*aria2test.Server, n)
+	paths := make([]string, n)
+	for i := range servers {
+		srv := aria2test.NewServer(nil)
+		t.Cleanup(srv.Close)
+		servers[i] = srv
+		dir := t.TempDir()
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			a, err := aria2.NewAria2(append(srv.Options(), aria2.WithClock(clock))...)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if err := a.Attach(); err != nil {
+				t.Error(err)
+				return
+			}
+			defer a.Stop()
+			paths[i], err = a.DownloadWithOptions("http://example.com/file.bin", aria2.DownloadOptions{Dir: dir}, nil)
+			if err != nil {
+				t.Errorf("实例 %d 下载失败: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if t.Failed() {
+		return
+	}
+
+	if paths[0] == paths[1] {
+		t.Fatalf("两个实例的下载路径相同: %s", paths[0])
+	}
+	for i, srv := range servers {
+		if got := count(srv.Calls(), "aria2.addUri"); got != 1 {
+			t.Errorf("实例 %d 的服务器收到 %d 次 addUri，期望 1 次", i, got)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package aria2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDraining 服务正在排空，不再接受新任务
+var ErrDraining = errors.New("aria2 正在停止，不再接受新任务")
+
+// Drain 平滑停止服务：不再接受新任务，等待正在下载和等待中的任务结束，
+// 保存会话（设置了 WithSaveSession 时）后停止 aria2c。
+// ctx 超时或取消时不再等待，返回仍未完成的任务 GID 和 ctx 的错误。暂停的任务不会等待。
+func (a *Aria2) Drain(ctx context.Context) (incomplete []string, err error) {
+	a.mu.Lock()
+	a.draining = true
+	a.mu.Unlock()
+	// 未能停止时恢复接受新任务，避免实例一直处于排空状态
+	stopped := false
+	defer func() {
+		if !stopped {
+			a.mu.Lock()
+			a.draining = false
+			a.mu.Unlock()
+		}
+	}()
+
+	ticker := a.clock().NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	var waitErr error
+wait:
+	for {
+		gids, err := a.unfinishedGIDs()
+		if err != nil {
+			return nil, err
+		}
+		if len(gids) == 0 {
+			break
+		}
+		select {
+		case <-ticker.C():
+		case <-ctx.Done():
+			incomplete = gids
+			waitErr = ctx.Err()
+			break wait
+		}
+	}
+
+	if a.cfg.saveSession != "" {
+		if err := a.SaveSession(); err != nil {
+			return incomplete, fmt.Errorf("保存会话失败: %w", err)
+		}
+	}
+	if err := a.Stop(); err != nil {
+		return incomplete, err
+	}
+	stopped = true
+	return incomplete, waitErr
+}
+
+// waitingPageSize 分页查询等待中的任务时每页的数量
+const waitingPageSize = 1000
+
+// unfinishedGIDs 获取正在下载和等待中（不含暂停）的任务
+func (a *Aria2) unfinishedGIDs() ([]string, error) {
+	keys := []string{"gid", "status"}
+	active, err := a.TellActive(keys...)
+	if err != nil {
+		return nil, err
+	}
+	var gids []string
+	for _, status := range active {
+		gids = append(gids, status.GID)
+	}
+
+	// 分页获取所有等待中的任务
+	for offset := 0; ; offset += waitingPageSize {
+		waiting, err := a.TellWaiting(offset, waitingPageSize, keys...)
+		if err != nil {
+			return nil, err
+		}
+		for _, status := range waiting {
+			if status.Status == "waiting" {
+				gids = append(gids, status.GID)
+			}
+		}
+		if len(waiting) < waitingPageSize {
+			break
+		}
+	}
+	return gids, nil
+}
+
+// checkAccepting 检查是否可以添加新任务
+func (a *Aria2) checkAccepting() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.draining {
+		return ErrDraining
+	}
+	return nil
+}
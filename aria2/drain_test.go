@@ -0,0 +1,33 @@
+package aria2_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dxcweb/go-aria2/aria2"
+)
+
+func TestDrainErrorKeepsAccepting(t *testing.T) {
+	opts := newRPCServer(t, func(req rpcRequest) (interface{}, error) {
+		switch req.Method {
+		case "aria2.tellActive":
+			return nil, &aria2.RPCError{Code: 1, Message: "internal error"}
+		case "aria2.addUri":
+			return "2089b05ecca3d829", nil
+		}
+		return "OK", nil
+	})
+	a, err := aria2.NewAria2(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := a.Drain(context.Background()); err == nil {
+		t.Fatal("tellActive 失败时 Drain 应返回错误")
+	}
+	// 排空失败后实例没有停止，应继续接受新任务
+	if _, err := a.AddUri("http://example.com/file.bin", ""); errors.Is(err, aria2.ErrDraining) {
+		t.Fatalf("Drain 失败后仍拒绝新任务: %v", err)
+	}
+}
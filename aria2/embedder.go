@@ -1,11 +1,16 @@
 package aria2
 
 import (
+	"crypto/sha256"
 	_ "embed"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"sync"
 )
 
 // 嵌入不同平台的Aria2c二进制文件
@@ -20,6 +25,9 @@ var aria2cLinux []byte
 //go:embed binaries/aria2c-darwin
 var aria2cDarwin []byte
 
+// extractMu 保证同一进程内只有一个实例在提取二进制文件
+var extractMu sync.Mutex
+
 // GetEmbeddedBinaryData 根据当前平台返回对应的二进制文件数据
 func GetEmbeddedBinaryData() ([]byte, error) {
 	switch runtime.GOOS {
@@ -50,48 +58,146 @@ func GetEmbeddedBinaryName() (string, error) {
 
 // ExtractBinary 将嵌入的二进制文件提取到app目录
 func ExtractBinary() (string, error) {
-	filename, err := GetEmbeddedBinaryName()
-	if err != nil {
-		return "", err
-	}
-
 	// 获取跨平台的应用数据目录
 	appDir, err := getAppDataDir()
 	if err != nil {
 		return "", fmt.Errorf("无法获取应用程序数据目录: %w", err)
 	}
+	return extractBinary(appDir)
+}
+
+// extractBinary 将嵌入的二进制文件提取到 appDir 目录
+func extractBinary(appDir string) (string, error) {
+	filename, err := GetEmbeddedBinaryName()
+	if err != nil {
+		return "", err
+	}
 
 	// 构建二进制文件路径
 	binaryPath := filepath.Join(appDir, filename)
 
-	// 检查文件是否已存在
-	if _, err := os.Stat(binaryPath); err == nil {
-		// 文件已存在，直接返回路径
-		return binaryPath, nil
-	}
 	if err := CheckBinaryExists(); err != nil {
+		// 嵌入的是占位文件时，沿用磁盘上已有的二进制文件
+		if _, statErr := os.Stat(binaryPath); statErr == nil {
+			return binaryPath, nil
+		}
 		return "", err
 	}
 
-	err = os.MkdirAll(appDir, 0755)
+	data, err := GetEmbeddedBinaryData()
 	if err != nil {
-		return "", fmt.Errorf("创建应用程序目录失败: %w", err)
+		return "", fmt.Errorf("无法获取嵌入的二进制文件数据: %w", err)
 	}
+	hash := binaryHash(data)
 
-	data, err := GetEmbeddedBinaryData()
+	// 已提取的文件与嵌入版本一致时直接返回路径
+	if isBinaryUpToDate(binaryPath, hash) {
+		return binaryPath, nil
+	}
+
+	// 多个实例同时首次启动时串行提取，避免互相覆盖
+	extractMu.Lock()
+	defer extractMu.Unlock()
+	if isBinaryUpToDate(binaryPath, hash) {
+		return binaryPath, nil
+	}
+
+	err = os.MkdirAll(appDir, 0755)
 	if err != nil {
-		return "", fmt.Errorf("无法获取嵌入的二进制文件数据: %w", err)
+		return "", fmt.Errorf("创建应用程序目录失败: %w", err)
 	}
 
 	// 写入二进制文件
-	err = os.WriteFile(binaryPath, data, 0755)
+	err = writeFileAtomic(binaryPath, data, 0755)
 	if err != nil {
 		return "", fmt.Errorf("写入二进制文件失败: %w", err)
 	}
+	// 记录版本，用于下次判断是否需要重新提取
+	err = writeFileAtomic(binaryPath+".version", []byte(hash), 0644)
+	if err != nil {
+		return "", fmt.Errorf("写入版本文件失败: %w", err)
+	}
 
 	return binaryPath, nil
 }
 
+// writeFileAtomic 先写入临时文件再重命名，其他进程不会读到写了一半的文件
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// ErrBinaryQuarantined 提取的 aria2c 在启动前消失，通常是被杀毒软件删除或隔离
+var ErrBinaryQuarantined = errors.New("aria2c 二进制文件被删除或隔离，请检查杀毒软件并将其加入白名单")
+
+// ensureExtractedBinary 确认提取的二进制文件存在且大小正确
+// 文件丢失时重新提取一次，再次丢失则返回 ErrBinaryQuarantined
+func ensureExtractedBinary(binaryPath string) error {
+	if isExtractedBinaryIntact(binaryPath) {
+		return nil
+	}
+	if _, err := extractBinary(filepath.Dir(binaryPath)); err != nil {
+		return err
+	}
+	if !isExtractedBinaryIntact(binaryPath) {
+		return fmt.Errorf("%w: %s", ErrBinaryQuarantined, binaryPath)
+	}
+	return nil
+}
+
+// isExtractedBinaryIntact 检查磁盘上的二进制文件是否存在且与嵌入文件大小一致
+func isExtractedBinaryIntact(binaryPath string) bool {
+	info, err := os.Stat(binaryPath)
+	if err != nil {
+		return false
+	}
+	// 嵌入的是占位文件时无法比较大小，只要求文件存在
+	if CheckBinaryExists() != nil {
+		return true
+	}
+	data, err := GetEmbeddedBinaryData()
+	if err != nil {
+		return false
+	}
+	return info.Size() == int64(len(data))
+}
+
+// binaryHash 计算二进制文件内容的 SHA-256
+func binaryHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// isBinaryUpToDate 检查已提取的二进制文件是否与嵌入版本一致
+// 版本文件不一致（升级了依赖）或文件内容被篡改时都需要重新提取
+func isBinaryUpToDate(binaryPath, hash string) bool {
+	version, err := os.ReadFile(binaryPath + ".version")
+	if err != nil || strings.TrimSpace(string(version)) != hash {
+		return false
+	}
+	data, err := os.ReadFile(binaryPath)
+	if err != nil {
+		return false
+	}
+	return binaryHash(data) == hash
+}
+
 // CheckBinaryExists 检查二进制文件是否存在
 func CheckBinaryExists() error {
 	data, err := GetEmbeddedBinaryData()
@@ -100,7 +206,7 @@ func CheckBinaryExists() error {
 	}
 	// 检查是否为占位文件
 	if len(data) <= 2 {
-		return fmt.Errorf("未找到 aria2c 二进制文件 - 请先运行下载脚本")
+		return ErrNoEmbeddedBinary
 	}
 
 	return nil
@@ -0,0 +1,102 @@
+package aria2
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ConfigFromEnv 从环境变量读取配置，返回可传给 NewAria2 的选项
+// 未设置的环境变量使用默认值，格式错误或超出范围时返回包含所有错误项的错误
+//
+//	ARIA2_PORT            RPC 端口
+//	ARIA2_SECRET          RPC 密钥
+//	ARIA2_MAX_CONCURRENT  最大同时下载任务数
+//	ARIA2_DISK_CACHE      磁盘缓存大小，支持 K/M/G 后缀，如 64M，0 表示禁用
+//	ARIA2_DATA_DIR        aria2c 二进制文件的提取目录
+//	ARIA2_DOWNLOAD_DIR    默认下载目录
+func ConfigFromEnv() ([]Option, error) {
+	var opts []Option
+	var errs []error
+
+	if v, ok := lookupEnv("ARIA2_PORT"); ok {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("ARIA2_PORT 格式错误: %q", v))
+		} else if port < 1 || port > 65535 {
+			errs = append(errs, fmt.Errorf("ARIA2_PORT 超出范围 1-65535: %d", port))
+		} else {
+			opts = append(opts, WithPort(port))
+		}
+	}
+	if v, ok := lookupEnv("ARIA2_SECRET"); ok {
+		opts = append(opts, WithSecret(v))
+	}
+	if v, ok := lookupEnv("ARIA2_MAX_CONCURRENT"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("ARIA2_MAX_CONCURRENT 格式错误: %q", v))
+		} else if n <= 0 {
+			errs = append(errs, fmt.Errorf("ARIA2_MAX_CONCURRENT 必须大于0: %d", n))
+		} else {
+			opts = append(opts, WithMaxConcurrentDownloads(n))
+		}
+	}
+	if v, ok := lookupEnv("ARIA2_DISK_CACHE"); ok {
+		size, err := parseSize(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("ARIA2_DISK_CACHE 格式错误: %q: %w", v, err))
+		} else if size > math.MaxInt {
+			errs = append(errs, fmt.Errorf("ARIA2_DISK_CACHE 超出范围: %q", v))
+		} else {
+			opts = append(opts, WithDiskCache(int(size)))
+		}
+	}
+	if v, ok := lookupEnv("ARIA2_DATA_DIR"); ok {
+		opts = append(opts, WithDataDir(v))
+	}
+	if v, ok := lookupEnv("ARIA2_DOWNLOAD_DIR"); ok {
+		opts = append(opts, WithDefaultDir(v))
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return opts, nil
+}
+
+// lookupEnv 读取环境变量，未设置或为空时返回 false
+func lookupEnv(key string) (string, bool) {
+	v := strings.TrimSpace(os.Getenv(key))
+	return v, v != ""
+}
+
+// parseSize 解析带 K/M/G 后缀的大小（与 aria2 一致，按 1024 换算）
+func parseSize(s string) (int64, error) {
+	multiplier := int64(1)
+	switch strings.ToUpper(s[len(s)-1:]) {
+	case "K":
+		multiplier = 1 << 10
+	case "M":
+		multiplier = 1 << 20
+	case "G":
+		multiplier = 1 << 30
+	}
+	if multiplier > 1 {
+		s = s[:len(s)-1]
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("大小不能为负数: %d", n)
+	}
+	if n > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("大小超出范围: %d", n)
+	}
+	return n * multiplier, nil
+}
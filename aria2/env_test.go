@@ -0,0 +1,94 @@
+package aria2
+
+import (
+	"strings"
+	"testing"
+)
+
+// envKeys ConfigFromEnv 读取的全部环境变量
+var envKeys = []string{"ARIA2_PORT", "ARIA2_SECRET", "ARIA2_MAX_CONCURRENT", "ARIA2_DISK_CACHE", "ARIA2_DATA_DIR", "ARIA2_DOWNLOAD_DIR"}
+
+// setEnv 清空全部环境变量后设置 env
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, key := range envKeys {
+		t.Setenv(key, env[key])
+	}
+}
+
+func TestConfigFromEnvUnset(t *testing.T) {
+	setEnv(t, nil)
+	opts, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opts) != 0 {
+		t.Fatalf("未设置环境变量时返回了 %d 个选项", len(opts))
+	}
+}
+
+func TestConfigFromEnvValid(t *testing.T) {
+	dataDir, downloadDir := t.TempDir(), t.TempDir()
+	setEnv(t, map[string]string{
+		"ARIA2_PORT":           "16800",
+		"ARIA2_SECRET":         "s3cret",
+		"ARIA2_MAX_CONCURRENT": "3",
+		"ARIA2_DISK_CACHE":     "64M",
+		"ARIA2_DATA_DIR":       dataDir,
+		"ARIA2_DOWNLOAD_DIR":   downloadDir,
+	})
+	opts, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := newInstance(t, opts...)
+	if a.port != 16800 || a.cfg.secret != "s3cret" || a.cfg.maxConcurrentDownloads != 3 || a.cfg.diskCache != 64<<20 {
+		t.Fatalf("端口 %d、密钥 %q、同时下载数 %d、磁盘缓存 %d 与环境变量不符",
+			a.port, a.cfg.secret, a.cfg.maxConcurrentDownloads, a.cfg.diskCache)
+	}
+	if a.cfg.dataDir != dataDir || a.cfg.defaultDir != downloadDir {
+		t.Fatalf("数据目录 %q、下载目录 %q 与环境变量不符", a.cfg.dataDir, a.cfg.defaultDir)
+	}
+}
+
+func TestConfigFromEnvMalformed(t *testing.T) {
+	tests := []struct {
+		key, value string
+	}{
+		{"ARIA2_PORT", "abc"},
+		{"ARIA2_PORT", "0"},
+		{"ARIA2_PORT", "70000"},
+		{"ARIA2_MAX_CONCURRENT", "many"},
+		{"ARIA2_MAX_CONCURRENT", "0"},
+		{"ARIA2_DISK_CACHE", "64X"},
+		{"ARIA2_DISK_CACHE", "M"},
+		{"ARIA2_DISK_CACHE", "-1M"},
+		{"ARIA2_DISK_CACHE", "99999999999G"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			setEnv(t, map[string]string{tt.key: tt.value})
+			_, err := ConfigFromEnv()
+			if err == nil || !strings.Contains(err.Error(), tt.key) {
+				t.Fatalf("错误为 %v，期望指出 %s", err, tt.key)
+			}
+		})
+	}
+}
+
+func TestConfigFromEnvReportsAllErrors(t *testing.T) {
+	setEnv(t, map[string]string{"ARIA2_PORT": "abc", "ARIA2_DISK_CACHE": "1T"})
+	_, err := ConfigFromEnv()
+	if err == nil || !strings.Contains(err.Error(), "ARIA2_PORT") || !strings.Contains(err.Error(), "ARIA2_DISK_CACHE") {
+		t.Fatalf("错误为 %v，期望同时指出两个环境变量", err)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]int64{"0": 0, "512": 512, "16k": 16 << 10, "64M": 64 << 20, "2G": 2 << 30}
+	for s, want := range tests {
+		if got, err := parseSize(s); err != nil || got != want {
+			t.Errorf("parseSize(%q) = %d, %v，期望 %d", s, got, err, want)
+		}
+	}
+}
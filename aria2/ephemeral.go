@@ -0,0 +1,63 @@
+package aria2
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// WithEphemeralBinary 将 aria2c 提取到临时目录而不是应用数据目录，
+// 进程退出或调用 Close 后删除该临时目录，适合不希望在磁盘上留下文件的场景
+func WithEphemeralBinary(enabled bool) Option {
+	return func(a *Aria2) error {
+		a.cfg.ephemeralBinary = enabled
+		return nil
+	}
+}
+
+// extractEphemeral 将二进制文件提取到新建的临时目录
+func (a *Aria2) extractEphemeral() (string, error) {
+	dir, err := os.MkdirTemp("", "go-aria2-*")
+	if err != nil {
+		return "", fmt.Errorf("无法创建临时目录: %w", err)
+	}
+	binaryPath, err := extractBinary(dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	a.ephemeralDir = dir
+	return binaryPath, nil
+}
+
+// removeEphemeralDir 删除临时目录
+// Windows 上进程退出后文件句柄可能还会短暂占用，失败时稍后重试
+func removeEphemeralDir(dir string) error {
+	var err error
+	for i := 0; i < 20; i++ {
+		if err = os.RemoveAll(dir); err == nil {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("无法删除临时目录 %s: %w", dir, err)
+}
+
+// Close 停止 aria2c 进程并等待其退出，使用 WithEphemeralBinary 时会等临时目录删除后再返回
+func (a *Aria2) Close() error {
+	a.mu.Lock()
+	exited := a.exited
+	a.mu.Unlock()
+	if err := a.Stop(); err != nil {
+		return err
+	}
+	if exited == nil {
+		return nil
+	}
+	select {
+	case <-exited:
+		return nil
+	case <-time.After(10 * time.Second):
+		return fmt.Errorf("等待 aria2c 进程退出超时")
+	}
+}
@@ -0,0 +1,54 @@
+package aria2
+
+import "fmt"
+
+// DownloadError 下载任务进入 error 状态时返回的错误
+type DownloadError struct {
+	GID     string          // 下载任务的GID
+	Code    string          // aria2 错误代码
+	Message string          // aria2 错误信息
+	Status  *DownloadStatus // 出错时的任务状态
+}
+
+func (e *DownloadError) Error() string {
+	return fmt.Sprintf("下载出错: %s", e.Message)
+}
+
+// newDownloadError 根据任务状态生成下载错误
+func newDownloadError(status *DownloadStatus) *DownloadError {
+	return &DownloadError{
+		GID:     status.GID,
+		Code:    status.ErrorCode,
+		Message: status.ErrorMessage,
+		Status:  status,
+	}
+}
+
+// recoverableErrorCodes 临时性的 aria2 错误代码，重试可能恢复
+// 参考 aria2 文档 EXIT STATUS 一节
+var recoverableErrorCodes = map[string]bool{
+	"2":  true, // 超时
+	"5":  true, // 速度过低而中止
+	"6":  true, // 网络问题
+	"19": true, // 域名解析失败
+	"22": true, // HTTP 响应头错误或异常
+	"23": true, // 重定向次数过多
+	"29": true, // 服务器暂时过载（如 HTTP 503）
+}
+
+// IsRecoverable 判断任务当前的错误是否为临时性错误，重试可能恢复
+// 任务仍在进行（active、waiting、paused）时 aria2 会自动重试，返回 true；
+// 已进入 error 状态时根据错误代码判断，例如超时、网络问题返回 true，文件不存在、磁盘空间不足返回 false；
+// 已完成或已被移除的任务返回 false
+func (s *DownloadStatus) IsRecoverable() bool {
+	switch s.Status {
+	case "complete", "removed":
+		return false
+	case "error":
+		return recoverableErrorCodes[s.ErrorCode]
+	}
+	if s.ErrorCode == "" || s.ErrorCode == "0" {
+		return true
+	}
+	return recoverableErrorCodes[s.ErrorCode]
+}
@@ -0,0 +1,67 @@
+package aria2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoProgress 当前全局下载速度为 0，无法估算剩余时间
+var ErrNoProgress = errors.New("当前下载速度为 0，无法估算剩余时间")
+
+// BatchETA 估算 gids 中所有任务全部完成还需要的时间
+// 剩余字节数为各任务 总大小 - 已完成大小 之和，除以 aria2.getGlobalStat 返回的全局下载速度；
+// 所有任务状态和全局统计通过一次批量调用获取。已结束的任务和尚未得知大小的任务不计入剩余字节数，
+// 全局速度包含不在 gids 中的任务。全部任务都已完成时返回 0，速度为 0 时返回 ErrNoProgress
+func (a *Aria2) BatchETA(gids []string) (time.Duration, error) {
+	keys := []string{"gid", "status", "totalLength", "completedLength"}
+	calls := make([]MethodCall, 0, len(gids)+1)
+	for _, gid := range gids {
+		calls = append(calls, MethodCall{MethodName: "aria2.tellStatus", Params: []interface{}{gid, keys}})
+	}
+	calls = append(calls, MethodCall{MethodName: "aria2.getGlobalStat"})
+	results, err := a.Multicall(calls)
+	if err != nil {
+		return 0, err
+	}
+
+	var remaining int64
+	for i, result := range results[:len(gids)] {
+		if result.Error != nil {
+			return 0, fmt.Errorf("查询任务 %s 失败: %w", gids[i], result.Error)
+		}
+		var status DownloadStatus
+		if err := json.Unmarshal(result.Result, &status); err != nil {
+			return 0, fmt.Errorf("解析状态失败: %w", err)
+		}
+		if status.State().Done() {
+			continue
+		}
+		total, ok := status.TotalBytes()
+		if !ok || total == 0 {
+			continue
+		}
+		completed, _ := status.CompletedBytes()
+		if completed < total {
+			remaining += total - completed
+		}
+	}
+	if remaining == 0 {
+		return 0, nil
+	}
+
+	statResult := results[len(gids)]
+	if statResult.Error != nil {
+		return 0, fmt.Errorf("获取全局统计信息失败: %w", statResult.Error)
+	}
+	var stat GlobalStat
+	if err := json.Unmarshal(statResult.Result, &stat); err != nil {
+		return 0, fmt.Errorf("解析全局统计信息失败: %w", err)
+	}
+	speed, ok := parseCount(stat.DownloadSpeed)
+	if !ok || speed == 0 {
+		return 0, ErrNoProgress
+	}
+	return time.Duration(float64(remaining) / float64(speed) * float64(time.Second)), nil
+}
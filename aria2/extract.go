@@ -0,0 +1,216 @@
+package aria2
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WithRemoveArchive DownloadAndExtract 解压成功后删除下载的压缩包
+func WithRemoveArchive() Option {
+	return func(a *Aria2) error {
+		a.cfg.removeArchive = true
+		return nil
+	}
+}
+
+// DownloadAndExtract 下载压缩包并解压到 dir，支持 zip、tar、tar.gz（tgz）
+// 根据文件头判断格式，无法判断时参考扩展名；解压时拒绝指向 dir 之外的路径（zip-slip）。
+// 压缩包中只有一个顶层目录时返回该目录，否则返回 dir 的绝对路径
+func (a *Aria2) DownloadAndExtract(url, dir string) (extractedDir string, err error) {
+	dir, err = a.ResolveDir(dir)
+	if err != nil {
+		return "", err
+	}
+	archivePath, err := a.download([]string{url}, DownloadOptions{Dir: dir}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	top, err := extractArchive(archivePath, dir)
+	if err != nil {
+		return "", err
+	}
+	if a.cfg.removeArchive {
+		if err := os.Remove(archivePath); err != nil {
+			return "", fmt.Errorf("删除压缩包失败: %w", err)
+		}
+	}
+	if top != "" {
+		return filepath.Join(dir, top), nil
+	}
+	return dir, nil
+}
+
+// extractArchive 解压 archivePath 到 dest，返回唯一的顶层目录名（没有或不唯一时为空）
+func extractArchive(archivePath, dest string) (string, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("打开压缩包失败: %w", err)
+	}
+	defer file.Close()
+
+	header := make([]byte, 512)
+	n, _ := io.ReadFull(file, header)
+	header = header[:n]
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	name := strings.ToLower(archivePath)
+	x := &extractor{dest: dest}
+	switch {
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")) || strings.HasSuffix(name, ".zip"):
+		info, err := file.Stat()
+		if err != nil {
+			return "", err
+		}
+		err = x.zip(file, info.Size())
+		return x.top(), err
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}) || strings.HasSuffix(name, ".gz") || strings.HasSuffix(name, ".tgz"):
+		gz, err := gzip.NewReader(bufio.NewReader(file))
+		if err != nil {
+			return "", fmt.Errorf("解压 gzip 失败: %w", err)
+		}
+		defer gz.Close()
+		err = x.tar(gz)
+		return x.top(), err
+	case isTarHeader(header) || strings.HasSuffix(name, ".tar"):
+		err = x.tar(bufio.NewReader(file))
+		return x.top(), err
+	}
+	return "", fmt.Errorf("不支持的压缩包格式: %s", filepath.Base(archivePath))
+}
+
+// isTarHeader 根据 ustar 标记判断是否为 tar 文件
+func isTarHeader(header []byte) bool {
+	return len(header) >= 262 && string(header[257:262]) == "ustar"
+}
+
+// extractor 解压文件到目标目录并记录顶层目录
+type extractor struct {
+	dest string
+	tops map[string]bool // 所有条目的第一级路径
+	file bool            // 是否有位于顶层的普通文件
+}
+
+// target 返回条目在目标目录中的路径，路径指向目标目录之外时返回错误
+func (x *extractor) target(name string) (string, error) {
+	name = filepath.FromSlash(name)
+	if filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("压缩包中包含绝对路径: %s", name)
+	}
+	target := filepath.Join(x.dest, name)
+	rel, err := filepath.Rel(x.dest, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("压缩包中的路径超出解压目录: %s", name)
+	}
+	if rel != "." {
+		if x.tops == nil {
+			x.tops = make(map[string]bool)
+		}
+		first, rest, _ := strings.Cut(rel, string(filepath.Separator))
+		x.tops[first] = true
+		if rest == "" {
+			x.file = x.file || !strings.HasSuffix(filepath.ToSlash(name), "/")
+		}
+	}
+	return target, nil
+}
+
+// top 返回唯一的顶层目录名
+func (x *extractor) top() string {
+	if len(x.tops) != 1 || x.file {
+		return ""
+	}
+	for name := range x.tops {
+		return name
+	}
+	return ""
+}
+
+// zip 解压 zip 文件
+func (x *extractor) zip(r io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("读取 zip 失败: %w", err)
+	}
+	for _, f := range zr.File {
+		target, err := x.target(f.Name)
+		if err != nil {
+			return err
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.Mode().IsRegular() {
+			// 不解压符号链接等特殊文件，避免指向解压目录之外
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("读取 %s 失败: %w", f.Name, err)
+		}
+		err = writeExtracted(target, rc, f.Mode())
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tar 解压 tar 流
+func (x *extractor) tar(r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取 tar 失败: %w", err)
+		}
+		target, err := x.target(hdr.Name)
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeExtracted(target, tr, hdr.FileInfo().Mode()); err != nil {
+				return err
+			}
+		}
+		// 符号链接、硬链接和设备文件不解压，避免指向解压目录之外
+	}
+}
+
+// writeExtracted 写入解压出的文件，只保留读写执行权限
+func writeExtracted(target string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0600)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	return f.Close()
+}
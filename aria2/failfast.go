@@ -0,0 +1,62 @@
+package aria2
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+)
+
+// ErrHTTPStatus 服务器返回了 4xx/5xx 状态码，可用 errors.Is 判断，
+// 具体状态码通过 DownloadError.HTTPStatus 获取
+var ErrHTTPStatus = errors.New("服务器返回了错误的 HTTP 状态码")
+
+// WithFailFastOnHTTPError 遇到 404、403 等 HTTP 错误时尽快失败，不等待 aria2 默认的多次重试
+// 启动时设置 --max-tries=1 和 --retry-wait=1，覆盖 WithMaxTries 和 WithRetryWait；
+// DownloadOptions 中指定的 MaxTries、RetryWait 仍然优先。适合批量检查地址是否有效
+func WithFailFastOnHTTPError(enabled bool) Option {
+	return func(a *Aria2) error {
+		a.cfg.failFast = enabled
+		return nil
+	}
+}
+
+// failFastArgs 在启动参数中应用快速失败设置
+func (a *Aria2) failFastArgs(args []string) []string {
+	if !a.cfg.failFast {
+		return args
+	}
+	args = setArg(args, "max-tries", "1")
+	return setArg(args, "retry-wait", "1")
+}
+
+// httpStatusPattern aria2 错误信息中的 HTTP 状态码，例如 "The response status is not successful. status=404"
+var httpStatusPattern = regexp.MustCompile(`status=(\d{3})\b`)
+
+// httpErrorCodes aria2 错误代码对应的 HTTP 状态码，错误信息中没有状态码时使用
+var httpErrorCodes = map[string]int{
+	"3":  404, // 资源不存在
+	"24": 401, // HTTP 认证失败
+	"29": 503, // 服务器暂时过载
+}
+
+// HTTPStatus 返回导致任务失败的 HTTP 状态码，不是 HTTP 状态错误时返回 false
+// 优先从错误信息中解析，解析不到时根据 aria2 错误代码推断
+func (e *DownloadError) HTTPStatus() (int, bool) {
+	if m := httpStatusPattern.FindStringSubmatch(e.Message); m != nil {
+		code, err := strconv.Atoi(m[1])
+		if err == nil && code >= 400 && code < 600 {
+			return code, true
+		}
+	}
+	code, ok := httpErrorCodes[e.Code]
+	return code, ok
+}
+
+// Is 让 errors.Is(err, ErrHTTPStatus) 对 HTTP 状态错误返回 true
+func (e *DownloadError) Is(target error) bool {
+	if target != ErrHTTPStatus {
+		return false
+	}
+	_, ok := e.HTTPStatus()
+	return ok
+}
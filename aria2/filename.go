@@ -0,0 +1,59 @@
+package aria2
+
+import "strings"
+
+// WithFilenameSanitizer 设置生成输出文件名时使用的清理函数，默认为 SanitizeFilename
+func WithFilenameSanitizer(sanitizer func(string) string) Option {
+	return func(a *Aria2) error {
+		a.cfg.filenameSanitizer = sanitizer
+		return nil
+	}
+}
+
+// sanitizeFilename 使用配置的清理函数处理由本包生成的文件名
+func (a *Aria2) sanitizeFilename(name string) string {
+	if a.cfg.filenameSanitizer != nil {
+		return a.cfg.filenameSanitizer(name)
+	}
+	return SanitizeFilename(name)
+}
+
+// windowsReservedNames Windows 上不能作为文件名的设备名
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// SanitizeFilename 清理文件名，使其在各平台上都可以安全使用
+// 去掉 "."、".." 等路径片段防止目录穿越，路径分隔符和 Windows 不允许的字符（:*?"<>| 等）替换为 "_"，
+// 结果为空或只包含 "." 时返回空字符串
+func SanitizeFilename(name string) string {
+	// 按路径分隔符拆分，丢弃可用于目录穿越的片段
+	var parts []string
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if part != "." && part != ".." {
+			parts = append(parts, part)
+		}
+	}
+	name = strings.Join(parts, "_")
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	// Windows 不允许文件名以空格或点结尾
+	name = strings.TrimRight(name, " .")
+	name = strings.TrimSpace(name)
+	if strings.Trim(name, ".") == "" {
+		return ""
+	}
+	base := strings.ToUpper(strings.SplitN(name, ".", 2)[0])
+	if windowsReservedNames[base] {
+		name = "_" + name
+	}
+	return name
+}
@@ -0,0 +1,65 @@
+package aria2
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Units 格式化字节数时使用的单位制
+type Units int
+
+const (
+	// BinaryUnits 二进制单位，按 1024 换算：KiB、MiB、GiB（默认）
+	BinaryUnits Units = iota
+	// DecimalUnits 十进制单位，按 1000 换算：kB、MB、GB
+	DecimalUnits
+)
+
+var (
+	binaryUnitNames  = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	decimalUnitNames = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}
+)
+
+// FormatBytes 使用二进制单位格式化字节数，如 "1.5 GiB"
+func FormatBytes(n int64) string {
+	return BinaryUnits.FormatBytes(n)
+}
+
+// FormatSpeed 使用二进制单位格式化速度（字节/秒），如 "3.2 MiB/s"
+func FormatSpeed(bytesPerSec int64) string {
+	return BinaryUnits.FormatSpeed(bytesPerSec)
+}
+
+// FormatBytes 使用指定单位制格式化字节数，不足 1 个单位时显示整数字节，否则保留一位小数
+func (u Units) FormatBytes(n int64) string {
+	base, names := 1024.0, binaryUnitNames
+	if u == DecimalUnits {
+		base, names = 1000.0, decimalUnitNames
+	}
+
+	sign := ""
+	value := float64(n)
+	if n < 0 {
+		sign = "-"
+		value = -value
+	}
+	if value < base {
+		return sign + strconv.FormatFloat(value, 'f', 0, 64) + " B"
+	}
+	i := 0
+	for value >= base && i < len(names)-1 {
+		value /= base
+		i++
+	}
+	// 四舍五入后达到下一个单位时进位，避免出现 "1024.0 KiB"
+	if value >= base-0.05 && i < len(names)-1 {
+		value /= base
+		i++
+	}
+	return fmt.Sprintf("%s%.1f %s", sign, value, names[i])
+}
+
+// FormatSpeed 使用指定单位制格式化速度（字节/秒）
+func (u Units) FormatSpeed(bytesPerSec int64) string {
+	return u.FormatBytes(bytesPerSec) + "/s"
+}
@@ -0,0 +1,62 @@
+package aria2
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultGlobalStatInterval 默认的全局统计推送间隔，比单个任务的轮询间隔长
+const defaultGlobalStatInterval = 2 * time.Second
+
+// WithGlobalStatInterval 设置 Subscribe 推送 EventGlobalStat 的间隔，默认 2 秒，0 表示不推送
+// aria2 不会主动推送全局统计，由本包按间隔调用 aria2.getGlobalStat 后发送到同一个事件 channel
+func WithGlobalStatInterval(d time.Duration) Option {
+	return func(a *Aria2) error {
+		if d < 0 {
+			return fmt.Errorf("全局统计间隔不能为负数: %v", d)
+		}
+		a.cfg.globalStatInterval = d
+		return nil
+	}
+}
+
+// startGlobalStats 启动全局统计轮询，返回的函数停止轮询并等待协程退出
+func (s *subscriber) startGlobalStats(ctx context.Context) (stop func()) {
+	interval := s.aria2.cfg.globalStatInterval
+	if interval <= 0 {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.pollGlobalStats(ctx, interval)
+	}()
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+// pollGlobalStats 定时查询全局统计并发送 EventGlobalStat，查询失败时跳过本次
+func (s *subscriber) pollGlobalStats(ctx context.Context, interval time.Duration) {
+	ticker := s.aria2.clock().NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C():
+		case <-ctx.Done():
+			return
+		}
+		stat, err := s.aria2.GetGlobalStat()
+		if err != nil {
+			continue
+		}
+		if !s.emit(ctx, Event{Type: EventGlobalStat, GlobalStat: stat}) {
+			return
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package aria2
+
+// WithHashCheckOnResume 设置恢复 BT 任务时是否重新校验已下载文件的分片哈希
+// 开启时对应 --check-integrity=true，大文件校验可能需要较长时间，期间可通过 DownloadStatus.Verifying
+// 和 VerifyProgress 显示校验进度；关闭时对应 --check-integrity=false 和 --bt-seed-unverified=true，
+// 直接信任控制文件记录的进度和已完成的文件。与 WithCheckIntegrity 设置同一个选项，以最后设置的为准
+func WithHashCheckOnResume(enabled bool) Option {
+	return func(a *Aria2) error {
+		a.cfg.checkIntegrity = enabled
+		a.cfg.seedUnverified = !enabled
+		return nil
+	}
+}
+
+// Verifying 任务是否正在校验或等待校验已有文件，此时 CompletedLength 不会增长
+func (s *DownloadStatus) Verifying() bool {
+	return s.VerifiedLength != "" || s.VerifyIntegrityPending == "true"
+}
+
+// VerifyProgress 返回校验进度：已校验的字节数和总字节数，等待校验时已校验为 0
+// 任务不在校验中时返回 false
+func (s *DownloadStatus) VerifyProgress() (verified, total int64, ok bool) {
+	if !s.Verifying() {
+		return 0, 0, false
+	}
+	return parseLength(s.VerifiedLength), parseLength(s.TotalLength), true
+}
@@ -0,0 +1,51 @@
+package aria2
+
+import "testing"
+
+func TestHashCheckOptionsLastWins(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "恢复时校验",
+			opts:    []Option{WithHashCheckOnResume(true)},
+			want:    []string{"--check-integrity=true"},
+			notWant: []string{"--bt-seed-unverified=true"},
+		},
+		{
+			name: "恢复时不校验",
+			opts: []Option{WithHashCheckOnResume(false)},
+			want: []string{"--check-integrity=false", "--bt-seed-unverified=true"},
+		},
+		{
+			name:    "WithCheckIntegrity 覆盖 WithHashCheckOnResume",
+			opts:    []Option{WithHashCheckOnResume(false), WithCheckIntegrity(true)},
+			want:    []string{"--check-integrity=true"},
+			notWant: []string{"--check-integrity=false", "--bt-seed-unverified=true"},
+		},
+		{
+			name:    "WithHashCheckOnResume 覆盖 WithCheckIntegrity",
+			opts:    []Option{WithCheckIntegrity(true), WithHashCheckOnResume(false)},
+			want:    []string{"--check-integrity=false", "--bt-seed-unverified=true"},
+			notWant: []string{"--check-integrity=true"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := newInstance(t, tt.opts...).buildArgs()
+			for _, arg := range tt.want {
+				if !hasArg(args, arg) {
+					t.Errorf("参数中缺少 %s: %v", arg, args)
+				}
+			}
+			for _, arg := range tt.notWant {
+				if hasArg(args, arg) {
+					t.Errorf("参数中不应包含 %s: %v", arg, args)
+				}
+			}
+		})
+	}
+}
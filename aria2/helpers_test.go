@@ -0,0 +1,121 @@
+package aria2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dxcweb/go-aria2/aria2"
+	"github.com/dxcweb/go-aria2/aria2/aria2test"
+)
+
+// attach 启动测试服务器并连接，测试结束时断开
+func attach(t *testing.T, script aria2test.Script, opts ...aria2.Option) (*aria2.Aria2, *aria2test.Server) {
+	t.Helper()
+	srv := aria2test.NewServer(script)
+	t.Cleanup(srv.Close)
+	a, err := aria2.NewAria2(append(srv.Options(), opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Attach(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { a.Stop() })
+	return a, srv
+}
+
+// attachWithClock 与 attach 相同，但使用 clock 作为时间来源
+// WaitReady 通过 clock 的 Ticker 等待，连接期间在后台推进时间，连接后时间只由测试推进
+func attachWithClock(t *testing.T, script aria2test.Script, clock *aria2test.Clock, opts ...aria2.Option) (*aria2.Aria2, *aria2test.Server) {
+	t.Helper()
+	defer advance(clock)()
+	return attach(t, script, append(opts, aria2.WithClock(clock))...)
+}
+
+// runClock 在后台不断推进 clock，直到测试结束
+// 用于轮询间隔由 clock 控制、测试只关心最终结果的场景
+func runClock(t *testing.T, clock *aria2test.Clock) {
+	t.Cleanup(advance(clock))
+}
+
+// advance 在后台每毫秒将 clock 推进 100ms，返回的函数停止推进
+func advance(clock *aria2test.Clock) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Millisecond):
+				clock.Advance(100 * time.Millisecond)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// count 统计 calls 中 method 出现的次数
+func count(calls []string, method string) int {
+	n := 0
+	for _, call := range calls {
+		if call == method {
+			n++
+		}
+	}
+	return n
+}
+
+// rpcRequest 测试服务器收到的 JSON-RPC 请求
+type rpcRequest struct {
+	ID     string            `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// newRPCServer 启动按 handler 返回结果的 JSON-RPC 服务器，返回连接它的选项
+// handler 返回的 error 为 *aria2.RPCError 时作为 JSON-RPC 错误返回
+func newRPCServer(t *testing.T, handler func(req rpcRequest) (interface{}, error)) []aria2.Option {
+	t.Helper()
+	return newRawRPCServer(t, func(req rpcRequest) map[string]interface{} {
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		result, err := handler(req)
+		if err != nil {
+			resp["error"] = err
+		} else {
+			resp["result"] = result
+		}
+		return resp
+	})
+}
+
+// newRawRPCServer 启动返回 handler 生成的完整响应的 JSON-RPC 服务器，用于构造异常响应
+func newRawRPCServer(t *testing.T, handler func(req rpcRequest) map[string]interface{}) []aria2.Option {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(handler(req))
+	}))
+	t.Cleanup(srv.Close)
+	return []aria2.Option{portOf(srv)}
+}
+
+// portOf 返回连接到测试服务器端口的选项
+func portOf(srv *httptest.Server) aria2.Option {
+	_, port, _ := strings.Cut(strings.TrimPrefix(srv.URL, "http://"), ":")
+	n, _ := strconv.Atoi(port)
+	return aria2.WithPort(n)
+}
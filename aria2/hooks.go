@@ -0,0 +1,67 @@
+package aria2
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// 事件脚本由 aria2c 进程直接执行，与 Go 回调相互独立，适合基于 shell 的后处理流程。
+// aria2 以命令行参数的形式传入三个值：任务 GID、文件数量、第一个文件的路径，
+// 例如 script.sh 2089b05ecca3d829 1 /downloads/file.iso；BT 任务的文件数可能大于 1，
+// 尚未确定文件路径时第三个参数为空字符串。脚本的工作目录和环境变量继承自 aria2c 进程
+
+// WithOnDownloadStartScript 任务开始下载时执行脚本，对应 --on-download-start
+func WithOnDownloadStartScript(path string) Option {
+	return hookScript(path, func(a *Aria2, p string) { a.cfg.onStartScript = p })
+}
+
+// WithOnDownloadCompleteScript 任务下载完成时执行脚本，对应 --on-download-complete
+// BT 任务在下载完成、做种结束后才执行
+func WithOnDownloadCompleteScript(path string) Option {
+	return hookScript(path, func(a *Aria2, p string) { a.cfg.onCompleteScript = p })
+}
+
+// WithOnDownloadErrorScript 任务出错时执行脚本，对应 --on-download-error
+func WithOnDownloadErrorScript(path string) Option {
+	return hookScript(path, func(a *Aria2, p string) { a.cfg.onErrorScript = p })
+}
+
+// hookScript 检查脚本存在且可执行，并转换为绝对路径（aria2c 的工作目录可能不同）
+func hookScript(path string, set func(a *Aria2, path string)) Option {
+	return func(a *Aria2) error {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("解析脚本路径失败: %w", err)
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			return fmt.Errorf("无法访问脚本: %w", err)
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("脚本不是普通文件: %s", abs)
+		}
+		// Windows 没有可执行权限位，由扩展名决定能否执行
+		if runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0 {
+			return fmt.Errorf("脚本没有可执行权限: %s", abs)
+		}
+		set(a, abs)
+		return nil
+	}
+}
+
+// hookArgs 返回事件脚本相关的启动参数
+func (a *Aria2) hookArgs() []string {
+	var args []string
+	if a.cfg.onStartScript != "" {
+		args = append(args, "--on-download-start="+a.cfg.onStartScript)
+	}
+	if a.cfg.onCompleteScript != "" {
+		args = append(args, "--on-download-complete="+a.cfg.onCompleteScript)
+	}
+	if a.cfg.onErrorScript != "" {
+		args = append(args, "--on-download-error="+a.cfg.onErrorScript)
+	}
+	return args
+}
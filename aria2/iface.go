@@ -0,0 +1,94 @@
+package aria2
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// WithInterface 指定下载使用的网络接口，对应 --interface
+// name 可以是接口名（如 eth0）或本机 IP 地址，启动前会检查接口是否存在
+func WithInterface(name string) Option {
+	return func(a *Aria2) error {
+		if name == "" {
+			return fmt.Errorf("网络接口不能为空")
+		}
+		if len(a.cfg.multipleInterfaces) > 0 {
+			return fmt.Errorf("WithInterface 不能与 WithMultipleInterfaces 同时使用")
+		}
+		a.cfg.iface = name
+		return nil
+	}
+}
+
+// WithMultipleInterfaces 指定多个网络接口，aria2 会把连接分散到这些接口上，对应 --multiple-interface
+// 只对 HTTP/FTP 生效，启动前会检查所有接口是否存在
+func WithMultipleInterfaces(names []string) Option {
+	return func(a *Aria2) error {
+		if len(names) == 0 {
+			return fmt.Errorf("网络接口列表不能为空")
+		}
+		if a.cfg.iface != "" {
+			return fmt.Errorf("WithMultipleInterfaces 不能与 WithInterface 同时使用")
+		}
+		for _, name := range names {
+			if name == "" || strings.Contains(name, ",") {
+				return fmt.Errorf("无效的网络接口: %q", name)
+			}
+		}
+		a.cfg.multipleInterfaces = append([]string(nil), names...)
+		return nil
+	}
+}
+
+// checkInterfaces 检查配置的网络接口在本机是否存在
+func (a *Aria2) checkInterfaces() error {
+	names := a.cfg.multipleInterfaces
+	if a.cfg.iface != "" {
+		names = []string{a.cfg.iface}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return fmt.Errorf("获取网络接口失败: %w", err)
+	}
+	available := make(map[string]bool)
+	var list []string
+	for _, iface := range interfaces {
+		available[iface.Name] = true
+		list = append(list, iface.Name)
+		// 同时允许使用接口上的 IP 地址
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok {
+				available[ipNet.IP.String()] = true
+			}
+		}
+	}
+	for _, name := range names {
+		if ip := net.ParseIP(name); ip != nil {
+			name = ip.String()
+		}
+		if !available[name] {
+			return fmt.Errorf("网络接口 %s 不存在，可用的接口: %s", name, strings.Join(list, ", "))
+		}
+	}
+	return nil
+}
+
+// interfaceArgs 返回网络接口相关的启动参数
+func (a *Aria2) interfaceArgs() []string {
+	if a.cfg.iface != "" {
+		return []string{"--interface=" + a.cfg.iface}
+	}
+	if len(a.cfg.multipleInterfaces) > 0 {
+		return []string{"--multiple-interface=" + strings.Join(a.cfg.multipleInterfaces, ",")}
+	}
+	return nil
+}
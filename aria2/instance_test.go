@@ -0,0 +1,22 @@
+package aria2
+
+import (
+	"slices"
+	"testing"
+)
+
+// newInstance 创建实例（不启动 aria2c），测试结束时释放分配的端口
+func newInstance(t *testing.T, opts ...Option) *Aria2 {
+	t.Helper()
+	a, err := NewAria2(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { releasePort(a.port) })
+	return a
+}
+
+// hasArg 命令行参数中是否包含 arg
+func hasArg(args []string, arg string) bool {
+	return slices.Contains(args, arg)
+}
@@ -0,0 +1,132 @@
+package aria2
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ErrIOStatsUnsupported 未设置日志文件，无法统计磁盘和连接信息
+var ErrIOStatsUnsupported = errors.New("未设置日志文件，无法统计 I/O 信息，请使用 WithLogFile")
+
+// IOStats 从 aria2 日志中统计的磁盘缓存和连接信息（尽力而为）
+// aria2 没有通过 RPC 提供这些数据，只能解析日志：连接和完成信息需要 info 级别，
+// 磁盘缓存信息需要 debug 级别（见 WithLogLevel），级别不够时对应字段为 0。
+// 统计从日志文件开头累计，日志文件被截断或替换时重新开始
+type IOStats struct {
+	CacheUpdates      int64 // 写入磁盘缓存的次数
+	CacheUpdatedBytes int64 // 写入磁盘缓存的字节数
+	CacheFlushes      int64 // 缓存超过 --disk-cache 上限被迫写入磁盘的次数，持续增长说明缓存偏小
+	CacheFlushedBytes int64 // 被迫写入磁盘的字节数
+	Connections       int64 // 发起的连接数
+	Completed         int64 // 完成的下载数
+	Warnings          int64 // WARN 级别的日志行数
+	Errors            int64 // ERROR 级别的日志行数
+}
+
+// FlushRatio 被迫写入磁盘的字节数占写入缓存字节数的比例，没有缓存数据时返回 0
+// 比例越接近 1，磁盘缓存的作用越小
+func (s *IOStats) FlushRatio() float64 {
+	if s.CacheUpdatedBytes == 0 {
+		return 0
+	}
+	return float64(s.CacheFlushedBytes) / float64(s.CacheUpdatedBytes)
+}
+
+var (
+	// cacheFlushPattern 缓存超过上限时强制写入的日志，例如 "Force flush cache entry size=1048576, clock=12"
+	cacheFlushPattern = regexp.MustCompile(`[Ff]lush cache entry size=(\d+)`)
+	// cacheUpdatePattern 写入缓存的日志，例如 "Update cache entry size=65536, clock=13"
+	cacheUpdatePattern = regexp.MustCompile(`(?:Update|Added) cache entry size=(\d+)`)
+)
+
+// ioStatsState 日志解析进度
+type ioStatsState struct {
+	path    string
+	info    os.FileInfo
+	offset  int64
+	partial string // 尚未读到换行符的部分行
+	stats   IOStats
+}
+
+// IOStats 解析日志文件中新增的内容并返回累计的统计信息
+// 未设置日志文件时返回 ErrIOStatsUnsupported
+func (a *Aria2) IOStats() (*IOStats, error) {
+	if a.cfg.logFile == "" {
+		return nil, ErrIOStatsUnsupported
+	}
+	a.ioStatsMu.Lock()
+	defer a.ioStatsMu.Unlock()
+
+	st := &a.ioStats
+	file, err := os.Open(a.cfg.logFile)
+	if os.IsNotExist(err) {
+		// aria2 还没有写入日志
+		stats := st.stats
+		return &stats, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("打开日志文件失败: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("读取日志文件信息失败: %w", err)
+	}
+	if st.path != a.cfg.logFile || st.info == nil || !os.SameFile(info, st.info) || info.Size() < st.offset {
+		*st = ioStatsState{path: a.cfg.logFile}
+	}
+	st.info = info
+
+	if _, err := file.Seek(st.offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("读取日志文件失败: %w", err)
+	}
+	reader := bufio.NewReader(file)
+	for {
+		chunk, err := reader.ReadString('\n')
+		st.offset += int64(len(chunk))
+		if strings.HasSuffix(chunk, "\n") {
+			st.stats.parseLine(strings.TrimRight(st.partial+chunk, "\r\n"))
+			st.partial = ""
+			continue
+		}
+		st.partial += chunk
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取日志文件失败: %w", err)
+		}
+	}
+	stats := st.stats
+	return &stats, nil
+}
+
+// parseLine 统计一行日志
+func (s *IOStats) parseLine(line string) {
+	switch {
+	case strings.Contains(line, "[ERROR]"):
+		s.Errors++
+	case strings.Contains(line, "[WARN]"):
+		s.Warnings++
+	}
+	if m := cacheFlushPattern.FindStringSubmatch(line); m != nil {
+		n, _ := parseCount(m[1])
+		s.CacheFlushes++
+		s.CacheFlushedBytes += n
+	} else if m := cacheUpdatePattern.FindStringSubmatch(line); m != nil {
+		n, _ := parseCount(m[1])
+		s.CacheUpdates++
+		s.CacheUpdatedBytes += n
+	}
+	if strings.Contains(line, " - Connecting to ") {
+		s.Connections++
+	}
+	if strings.Contains(line, "Download complete: ") {
+		s.Completed++
+	}
+}
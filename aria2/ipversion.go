@@ -0,0 +1,68 @@
+package aria2
+
+import "fmt"
+
+// IPVersion 下载使用的 IP 协议族
+type IPVersion int
+
+const (
+	// Both 同时使用 IPv4 和 IPv6（默认）
+	Both IPVersion = iota
+	// IPv4Only 只使用 IPv4，对应 --disable-ipv6=true
+	IPv4Only
+	// IPv6Only 优先使用 IPv6，本地 RPC 通过 [::1] 访问
+	// aria2 没有禁用 IPv4 的选项，服务器只有 IPv4 地址时仍会使用 IPv4
+	IPv6Only
+)
+
+// WithIPVersion 设置下载使用的 IP 协议族，用于网络声明了 IPv6 但无法路由时避免下载卡住
+// 本地端口检测和 RPC 连接也会使用对应的协议族
+func WithIPVersion(v IPVersion) Option {
+	return func(a *Aria2) error {
+		if v != Both && v != IPv4Only && v != IPv6Only {
+			return fmt.Errorf("无效的 IP 版本: %d", v)
+		}
+		a.cfg.ipVersion = v
+		// 自动分配的端口需要按新的协议族重新检测
+		if !a.cfg.portFixed {
+			releasePort(a.port)
+			a.port = findAvailablePortOn(a.listenNetwork(), 6800)
+		}
+		return nil
+	}
+}
+
+// listenNetwork 返回本地端口检测使用的网络类型
+func (a *Aria2) listenNetwork() string {
+	switch a.cfg.ipVersion {
+	case IPv4Only:
+		return "tcp4"
+	case IPv6Only:
+		return "tcp6"
+	}
+	return "tcp"
+}
+
+// rpcHost 返回访问本地 RPC 服务使用的地址
+func (a *Aria2) rpcHost() string {
+	if a.cfg.ipVersion == IPv6Only {
+		return "[::1]"
+	}
+	return "127.0.0.1"
+}
+
+// rpcURL 返回本地 RPC 服务的地址，scheme 为 http 或 ws，启用 TLS 时自动换成 https 或 wss
+func (a *Aria2) rpcURL(scheme string) string {
+	return fmt.Sprintf("%s://%s:%d/jsonrpc", a.rpcScheme(scheme), a.rpcHost(), a.port)
+}
+
+// ipVersionArgs 返回 IP 协议族相关的启动参数
+func (a *Aria2) ipVersionArgs() []string {
+	switch a.cfg.ipVersion {
+	case IPv4Only:
+		return []string{"--disable-ipv6=true"}
+	case IPv6Only:
+		return []string{"--disable-ipv6=false"}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package aria2
+
+import "fmt"
+
+// stoppedIterator IterateStopped 的分页状态
+type stoppedIterator struct {
+	a        *Aria2
+	pageSize int
+	offset   int                 // 下一页的起始位置
+	anchor   string              // 上一页最后一个任务的 GID，用于发现分页期间有任务被删除
+	seen     map[string]struct{} // 已返回的 GID，避免重复返回
+	done     bool
+}
+
+// IterateStopped 返回按页遍历已结束任务的迭代器，每次调用返回下一页、是否还有数据和错误
+// 迭代器内部通过 tellStopped 维护 offset，只在内存中保存已返回任务的 GID。
+// 分页期间新结束的任务会追加在末尾并被继续遍历到；若前面的任务被删除（如 PurgeDownloadResult）
+// 导致位置前移，迭代器会回退一页重新读取并去重，因此不会重复返回同一个任务。
+// 出错后可以再次调用重试当前页
+//
+//	next := a.IterateStopped(100)
+//	for {
+//		page, ok, err := next()
+//		if err != nil || !ok {
+//			break
+//		}
+//		...
+//	}
+func (a *Aria2) IterateStopped(pageSize int) func() ([]DownloadStatus, bool, error) {
+	it := &stoppedIterator{a: a, pageSize: pageSize, seen: make(map[string]struct{})}
+	return it.next
+}
+
+// next 读取下一页，跳过已返回的任务；整页都已返回过时继续读取，不返回空页
+func (it *stoppedIterator) next() ([]DownloadStatus, bool, error) {
+	if it.pageSize <= 0 {
+		return nil, false, fmt.Errorf("分页大小必须大于0: %d", it.pageSize)
+	}
+	for !it.done {
+		start, num := it.offset, it.pageSize
+		if it.anchor != "" {
+			// 多读一个上一页的最后一个任务，用于确认位置没有变化
+			start, num = it.offset-1, it.pageSize+1
+		}
+		list, err := it.a.TellStopped(start, num)
+		if err != nil {
+			return nil, false, err
+		}
+		if it.anchor != "" {
+			if len(list) == 0 || list[0].GID != it.anchor {
+				// 前面有任务被删除，回退一页重新读取
+				it.offset -= it.pageSize
+				if it.offset < 0 {
+					it.offset = 0
+				}
+				it.anchor = ""
+				continue
+			}
+			list = list[1:]
+		}
+
+		it.offset += len(list)
+		if len(list) < it.pageSize {
+			it.done = true
+		}
+		if len(list) > 0 {
+			it.anchor = list[len(list)-1].GID
+		}
+		page := make([]DownloadStatus, 0, len(list))
+		for _, status := range list {
+			if _, ok := it.seen[status.GID]; ok {
+				continue
+			}
+			it.seen[status.GID] = struct{}{}
+			page = append(page, status)
+		}
+		if len(page) > 0 {
+			return page, true, nil
+		}
+	}
+	return nil, false, nil
+}
@@ -0,0 +1,20 @@
+package aria2
+
+import "log"
+
+// WithLogger 设置警告等信息的日志输出，默认使用 log.Default()
+func WithLogger(logger *log.Logger) Option {
+	return func(a *Aria2) error {
+		a.cfg.logger = logger
+		return nil
+	}
+}
+
+// logf 输出日志
+func (a *Aria2) logf(format string, args ...interface{}) {
+	logger := a.cfg.logger
+	if logger == nil {
+		logger = log.Default()
+	}
+	logger.Printf("[aria2] "+format, args...)
+}
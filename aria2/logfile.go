@@ -0,0 +1,170 @@
+package aria2
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// tailPollInterval TailLog 检查日志文件新内容的间隔
+const tailPollInterval = 250 * time.Millisecond
+
+// WithLogFile 把 aria2 自己的日志写入文件，对应 --log，可通过 TailLog 实时读取
+// 文件中的日志级别由 --log-level 控制（默认 error）
+func WithLogFile(path string) Option {
+	return func(a *Aria2) error {
+		if path == "" {
+			return fmt.Errorf("日志文件路径不能为空")
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("解析日志文件路径失败: %w", err)
+		}
+		a.cfg.logFile = abs
+		return nil
+	}
+}
+
+// WithLogLevel 设置日志文件的级别，对应 --log-level，可选 debug、info、notice、warn、error（默认）
+// IOStats 中的磁盘缓存统计只出现在 debug 级别的日志中
+func WithLogLevel(level string) Option {
+	return func(a *Aria2) error {
+		switch level {
+		case "debug", "info", "notice", "warn", "error":
+		default:
+			return fmt.Errorf("无效的日志级别: %s", level)
+		}
+		a.cfg.logLevel = level
+		return nil
+	}
+}
+
+// TailLog 从当前末尾开始逐行读取 aria2 的日志文件，直到 ctx 取消时关闭返回的 channel
+// 日志文件被截断或轮转（被重命名后重新创建）时会从新文件的开头继续读取
+func (a *Aria2) TailLog(ctx context.Context) (<-chan string, error) {
+	if a.cfg.logFile == "" {
+		return nil, fmt.Errorf("未设置日志文件，请使用 WithLogFile")
+	}
+	t := &logTail{path: a.cfg.logFile}
+	// 文件可能在 aria2 启动后才创建，打开失败时稍后重试
+	if err := t.open(io.SeekEnd); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("打开日志文件失败: %w", err)
+	}
+
+	lines := make(chan string, 64)
+	go t.run(ctx, lines)
+	return lines, nil
+}
+
+// logTail 跟踪日志文件
+type logTail struct {
+	path    string
+	file    *os.File
+	info    os.FileInfo
+	offset  int64
+	partial []byte // 尚未读到换行符的部分行
+}
+
+// open 打开日志文件，whence 为 io.SeekEnd 时从末尾开始读取
+func (t *logTail) open(whence int) error {
+	file, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	offset, err := file.Seek(0, whence)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	t.close()
+	t.file, t.info, t.offset, t.partial = file, info, offset, nil
+	return nil
+}
+
+// close 关闭当前文件
+func (t *logTail) close() {
+	if t.file != nil {
+		t.file.Close()
+		t.file = nil
+	}
+}
+
+// run 定时读取新内容并按行发送
+func (t *logTail) run(ctx context.Context, lines chan<- string) {
+	defer close(lines)
+	defer t.close()
+
+	ticker := time.NewTicker(tailPollInterval)
+	defer ticker.Stop()
+	for {
+		if !t.read(ctx, lines) {
+			return
+		}
+		// 先读完旧文件剩余的内容，再切换到新文件
+		if t.checkRotation() && !t.read(ctx, lines) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// read 读取当前文件的新内容，ctx 取消时返回 false
+func (t *logTail) read(ctx context.Context, lines chan<- string) bool {
+	if t.file == nil {
+		return true
+	}
+	reader := bufio.NewReader(t.file)
+	for {
+		chunk, err := reader.ReadBytes('\n')
+		t.offset += int64(len(chunk))
+		if len(chunk) > 0 && chunk[len(chunk)-1] == '\n' {
+			line := strings.TrimRight(string(append(t.partial, chunk...)), "\r\n")
+			t.partial = nil
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return false
+			}
+			continue
+		}
+		t.partial = append(t.partial, chunk...)
+		if err != nil {
+			return true
+		}
+	}
+}
+
+// checkRotation 检查日志文件是否被截断或替换，是则从新文件开头读取，返回是否已切换
+func (t *logTail) checkRotation() bool {
+	info, err := os.Stat(t.path)
+	if err != nil {
+		// 轮转过程中文件可能暂时不存在
+		return false
+	}
+	if t.file == nil || !os.SameFile(info, t.info) {
+		return t.open(io.SeekStart) == nil
+	}
+	if info.Size() < t.offset {
+		// 文件被截断
+		if _, err := t.file.Seek(0, io.SeekStart); err == nil {
+			t.offset = 0
+			t.partial = nil
+			return true
+		}
+	}
+	return false
+}
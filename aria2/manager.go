@@ -0,0 +1,308 @@
+package aria2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Task 管理器中记录的下载任务
+type Task struct {
+	GID       string            // 下载任务的GID
+	URL       string            // 下载地址（已隐藏认证信息）
+	Dir       string            // 下载目录（绝对路径）
+	Out       string            // 输出文件名
+	StartedAt time.Time         // 添加时间
+	Status    *DownloadStatus   // 最近一次查询到的状态
+	Result    *DownloadResult   // 任务结束后的结果，未结束时为 nil
+	Metadata  map[string]string // 添加任务时指定的元数据
+}
+
+// Done 任务是否已结束（完成、出错或被删除）
+func (t *Task) Done() bool {
+	return t.Result != nil
+}
+
+// ErrQueueFull 未结束的任务数已达到上限
+var ErrQueueFull = errors.New("下载队列已满")
+
+// ErrManagerClosed 管理器已关闭
+var ErrManagerClosed = errors.New("任务管理器已关闭")
+
+// QueueFullMode 队列已满时 Add 的行为
+type QueueFullMode int
+
+const (
+	// QueueBlock 阻塞等待，直到有任务结束
+	QueueBlock QueueFullMode = iota
+	// QueueReject 立即返回 ErrQueueFull
+	QueueReject
+)
+
+// ManagerOption 创建 Manager 时使用的配置项
+type ManagerOption func(*Manager)
+
+// WithMaxQueue 限制未结束（等待、下载中、暂停）的任务数量，n <= 0 表示不限制
+// 达到上限后 Add 根据 mode 阻塞等待或返回 ErrQueueFull
+func WithMaxQueue(n int, mode QueueFullMode) ManagerOption {
+	return func(m *Manager) {
+		m.maxQueue = n
+		m.queueMode = mode
+	}
+}
+
+// Manager 下载任务管理器
+// 记录通过它添加的所有任务，由一个后台协程统一轮询状态
+type Manager struct {
+	aria2     *Aria2
+	interval  time.Duration
+	maxQueue  int
+	queueMode QueueFullMode
+
+	mu      sync.Mutex
+	cond    *sync.Cond // 有任务结束或被删除时通知等待中的 Add
+	tasks   map[string]*Task
+	pending int // 已占用队列位置但尚未添加完成的任务数
+	closed  bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewManager 创建任务管理器并启动后台轮询
+func NewManager(a *Aria2, opts ...ManagerOption) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		aria2:    a,
+		interval: time.Second,
+		tasks:    make(map[string]*Task),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	m.cond = sync.NewCond(&m.mu)
+	for _, opt := range opts {
+		opt(m)
+	}
+	go m.poll()
+	return m
+}
+
+// Close 停止后台轮询，不影响 aria2 中的任务
+func (m *Manager) Close() {
+	m.cancel()
+	m.mu.Lock()
+	m.closed = true
+	m.cond.Broadcast()
+	m.mu.Unlock()
+}
+
+// Add 添加下载任务并返回 GID
+// 设置了 WithMaxQueue 时，队列已满会阻塞或返回 ErrQueueFull
+// dir 为空且设置了 WithDiskPool 时从磁盘池中选择目录，可通过 Get 返回的 Task.Dir 获取
+func (m *Manager) Add(url, dir, out string) (string, error) {
+	return m.AddWithOptions(url, DownloadOptions{Dir: dir, Out: out})
+}
+
+// AddWithOptions 使用指定选项添加下载任务并返回 GID，opts.Metadata 会保存在任务记录中
+func (m *Manager) AddWithOptions(url string, opts DownloadOptions) (string, error) {
+	if err := m.acquire(); err != nil {
+		return "", err
+	}
+
+	gid, task, err := m.add(url, opts)
+
+	m.mu.Lock()
+	m.pending--
+	if err == nil {
+		m.tasks[gid] = task
+	} else {
+		m.cond.Broadcast()
+	}
+	m.mu.Unlock()
+	return gid, err
+}
+
+// add 调用 aria2 添加任务
+func (m *Manager) add(url string, opts DownloadOptions) (string, *Task, error) {
+	dir, err := m.aria2.chooseDir(opts.Dir)
+	if err != nil {
+		return "", nil, err
+	}
+	opts.Dir = dir
+	opts.Out = m.aria2.outputName(url, opts)
+	gid, err := m.aria2.AddUriWithOptions([]string{url}, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	return gid, &Task{
+		GID:       gid,
+		URL:       RedactURL(url),
+		Dir:       dir,
+		Out:       opts.Out,
+		StartedAt: m.aria2.clock().Now(),
+		Metadata:  copyMetadata(opts.Metadata),
+	}, nil
+}
+
+// acquire 占用一个队列位置
+func (m *Manager) acquire() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for {
+		if m.closed {
+			return ErrManagerClosed
+		}
+		if m.maxQueue <= 0 || m.activeCount()+m.pending < m.maxQueue {
+			m.pending++
+			return nil
+		}
+		if m.queueMode == QueueReject {
+			return ErrQueueFull
+		}
+		m.cond.Wait()
+	}
+}
+
+// activeCount 未结束的任务数，调用方需持有锁
+func (m *Manager) activeCount() int {
+	n := 0
+	for _, task := range m.tasks {
+		if !task.Done() {
+			n++
+		}
+	}
+	return n
+}
+
+// Get 获取任务信息的副本
+func (m *Manager) Get(gid string) (Task, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	task, ok := m.tasks[gid]
+	if !ok {
+		return Task{}, false
+	}
+	return *task, true
+}
+
+// List 按添加时间顺序返回所有任务的副本
+func (m *Manager) List() []Task {
+	m.mu.Lock()
+	tasks := make([]Task, 0, len(m.tasks))
+	for _, task := range m.tasks {
+		tasks = append(tasks, *task)
+	}
+	m.mu.Unlock()
+
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].StartedAt.Before(tasks[j].StartedAt)
+	})
+	return tasks
+}
+
+// Cancel 取消下载任务，任务记录会保留，状态变为 removed
+func (m *Manager) Cancel(gid string) error {
+	task, ok := m.Get(gid)
+	if !ok {
+		return fmt.Errorf("任务不存在: %s", gid)
+	}
+	if task.Done() {
+		return nil
+	}
+	return m.aria2.Remove(gid)
+}
+
+// Remove 删除任务记录，未结束的任务会先取消
+func (m *Manager) Remove(gid string) error {
+	task, ok := m.Get(gid)
+	if !ok {
+		return fmt.Errorf("任务不存在: %s", gid)
+	}
+	if !task.Done() {
+		if err := m.aria2.ForceRemove(gid); err != nil {
+			return err
+		}
+	}
+	// 清除 aria2 中保存的结果，失败不影响删除记录
+	m.aria2.RemoveDownloadResult(gid)
+
+	m.mu.Lock()
+	delete(m.tasks, gid)
+	m.cond.Broadcast()
+	m.mu.Unlock()
+	return nil
+}
+
+// poll 定时查询所有未结束任务的状态
+func (m *Manager) poll() {
+	ticker := m.aria2.clock().NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			m.refresh()
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// refresh 更新所有未结束任务的状态
+func (m *Manager) refresh() {
+	m.mu.Lock()
+	var gids []string
+	for gid, task := range m.tasks {
+		if !task.Done() {
+			gids = append(gids, gid)
+		}
+	}
+	m.mu.Unlock()
+
+	if len(gids) == 0 {
+		return
+	}
+	// 一次批量调用查询所有未结束的任务
+	results := m.aria2.pollStatuses(gids)
+	for i, gid := range gids {
+		status, err := results[i].status, results[i].err
+		if err != nil {
+			continue
+		}
+
+		m.mu.Lock()
+		if task, ok := m.tasks[gid]; ok {
+			task.Status = status
+			task.Result = taskResult(status)
+			if task.Result != nil {
+				task.Result.Dir = task.Dir
+				task.Result.Metadata = task.Metadata
+			}
+			if task.Done() {
+				m.cond.Broadcast()
+			}
+		}
+		m.mu.Unlock()
+	}
+}
+
+// taskResult 根据任务状态生成结果，任务未结束时返回 nil
+// Manager 不跟踪任务的耗时，结果中 Duration 和 AverageSpeed 为 0
+func taskResult(status *DownloadStatus) *DownloadResult {
+	var r *DownloadResult
+	switch status.Status {
+	case "complete":
+		r = &DownloadResult{Status: status}
+	case "error":
+		r = &DownloadResult{Status: status, Error: newDownloadError(status)}
+	case "removed":
+		r = &DownloadResult{Status: status, Error: fmt.Errorf("下载已取消")}
+	default:
+		return nil
+	}
+	r.fillFromStatus(status)
+	return r
+}
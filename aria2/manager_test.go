@@ -0,0 +1,44 @@
+package aria2_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dxcweb/go-aria2/aria2"
+	"github.com/dxcweb/go-aria2/aria2/aria2test"
+)
+
+func TestManagerTracksTaskUntilDone(t *testing.T) {
+	clock := aria2test.NewClock(time.Unix(0, 0))
+	a, _ := attachWithClock(t, nil, clock)
+	m := aria2.NewManager(a)
+	defer m.Close()
+
+	gid, err := m.Add("http://example.com/file.bin", t.TempDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	runClock(t, clock)
+
+	deadline := time.After(5 * time.Second)
+	for {
+		task, ok := m.Get(gid)
+		if !ok {
+			t.Fatalf("管理器中没有任务 %s", gid)
+		}
+		if task.Done() {
+			if task.Result.Error != nil {
+				t.Fatal(task.Result.Error)
+			}
+			if task.Status.Status != "complete" {
+				t.Fatalf("任务状态为 %s，期望 complete", task.Status.Status)
+			}
+			return
+		}
+		select {
+		case <-deadline:
+			t.Fatal("任务没有结束")
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package aria2
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrFileTooLarge 文件大小超过 WithMaxFileSize 设置的上限
+var ErrFileTooLarge = errors.New("文件大小超过上限")
+
+// WithMaxFileSize 限制单个任务的文件总大小，0 表示不限制（默认）
+// 首次得知总大小（通常来自响应头）时检查，超过上限的任务会被删除并返回 ErrFileTooLarge，
+// 此时通常只下载了很少的数据。不是断点续传的任务同时删除已创建的文件和 .aria2 控制文件
+func WithMaxFileSize(bytes int64) Option {
+	return func(a *Aria2) error {
+		if bytes < 0 {
+			return fmt.Errorf("最大文件大小不能为负数: %d", bytes)
+		}
+		a.cfg.maxFileSize = bytes
+		return nil
+	}
+}
+
+// checkFileSize 首次得知总大小时检查是否超过上限，超过时删除任务
+func (a *Aria2) checkFileSize(w *watch, status *DownloadStatus) error {
+	limit := a.cfg.maxFileSize
+	if limit <= 0 || w.sizeKnown {
+		return nil
+	}
+	total, ok := status.TotalBytes()
+	if !ok || total == 0 {
+		return nil
+	}
+	w.sizeKnown = true
+	if total <= limit {
+		return nil
+	}
+
+	if err := a.ForceRemove(w.gid); err != nil {
+		a.logf("删除超过大小上限的任务 %s 失败: %v", w.gid, err)
+	}
+	// 预分配可能已经创建了完整大小的文件；断点续传的文件是之前已有的，不删除
+	if !w.progress.wasResumed() {
+		for _, file := range status.Files {
+			if file.Path == "" {
+				continue
+			}
+			os.Remove(file.Path)
+			os.Remove(file.Path + ".aria2")
+		}
+	}
+	return fmt.Errorf("%w: %d 字节，上限为 %d 字节", ErrFileTooLarge, total, limit)
+}
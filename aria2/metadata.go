@@ -0,0 +1,45 @@
+package aria2
+
+// metadataRegistry 按 GID 保存任务的元数据，aria2 本身无法保存任意数据
+type metadataRegistry struct {
+	entries map[string]map[string]string
+}
+
+// Metadata 返回添加任务时通过 DownloadOptions.Metadata 指定的元数据副本，没有时返回 nil
+func (a *Aria2) Metadata(gid string) map[string]string {
+	a.metaMu.Lock()
+	defer a.metaMu.Unlock()
+	return copyMetadata(a.metadata.entries[gid])
+}
+
+// setMetadata 保存任务的元数据
+func (a *Aria2) setMetadata(gid string, metadata map[string]string) {
+	if len(metadata) == 0 {
+		return
+	}
+	a.metaMu.Lock()
+	defer a.metaMu.Unlock()
+	if a.metadata.entries == nil {
+		a.metadata.entries = make(map[string]map[string]string)
+	}
+	a.metadata.entries[gid] = copyMetadata(metadata)
+}
+
+// deleteMetadata 任务结果被清除时删除元数据
+func (a *Aria2) deleteMetadata(gid string) {
+	a.metaMu.Lock()
+	defer a.metaMu.Unlock()
+	delete(a.metadata.entries, gid)
+}
+
+// copyMetadata 复制元数据，避免调用方修改内部保存的数据
+func copyMetadata(metadata map[string]string) map[string]string {
+	if metadata == nil {
+		return nil
+	}
+	c := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,34 @@
+package aria2
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// AddMetalink 添加 Metalink 下载任务，data 为 .metalink 或 .meta4 文件内容
+// 一个 Metalink 文件可以描述多个文件，每个文件对应一个任务，返回所有任务的 GID
+func (a *Aria2) AddMetalink(data []byte, dir string) ([]string, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("Metalink 内容不能为空")
+	}
+	dir, err := a.ResolveDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.checkAccepting(); err != nil {
+		return nil, err
+	}
+	result, err := a.Call("aria2.addMetalink", []interface{}{
+		base64.StdEncoding.EncodeToString(data),
+		map[string]interface{}{"dir": dir},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var gids []string
+	if err := json.Unmarshal(result, &gids); err != nil {
+		return nil, fmt.Errorf("解析GID失败: %w", err)
+	}
+	return gids, nil
+}
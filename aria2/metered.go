@@ -0,0 +1,125 @@
+package aria2
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// WithMeteredLimit 设置计费网络下的全局下载速度上限（字节/秒），0 表示暂停所有任务（默认）
+func WithMeteredLimit(bytesPerSec int64) Option {
+	return func(a *Aria2) error {
+		if bytesPerSec < 0 {
+			return fmt.Errorf("速度限制不能为负数: %d", bytesPerSec)
+		}
+		a.cfg.meteredLimit = bytesPerSec
+		return nil
+	}
+}
+
+// meteredState 进入计费模式前保存的状态
+type meteredState struct {
+	enabled       bool
+	downloadLimit string   // 进入计费模式前的 max-overall-download-limit
+	paused        []string // 进入计费模式时由本包暂停的任务
+}
+
+// SetMetered 切换计费网络模式，检测网络是否计费由调用方负责
+//
+// metered 为 true 时保存当前的 max-overall-download-limit，然后按 WithMeteredLimit 限速；
+// 未设置限速时暂停所有正在下载和等待中的任务，并记录被暂停的任务。
+// metered 为 false 时恢复保存的速度限制，只恢复进入计费模式时由本函数暂停的任务，
+// 之前已经暂停的任务保持暂停。
+//
+// 保存的状态只在内存中，aria2c 重启后失效；计费模式期间对 max-overall-download-limit
+// 的修改会在退出计费模式时被覆盖。重复设置相同的值不做任何操作
+func (a *Aria2) SetMetered(metered bool) error {
+	a.meteredMu.Lock()
+	defer a.meteredMu.Unlock()
+	if a.metered.enabled == metered {
+		return nil
+	}
+	if metered {
+		return a.enterMetered()
+	}
+	return a.leaveMetered()
+}
+
+// IsMetered 是否处于计费网络模式
+func (a *Aria2) IsMetered() bool {
+	a.meteredMu.Lock()
+	defer a.meteredMu.Unlock()
+	return a.metered.enabled
+}
+
+// enterMetered 保存当前状态并限速或暂停任务
+func (a *Aria2) enterMetered() error {
+	options, err := a.GetGlobalOption()
+	if err != nil {
+		return fmt.Errorf("获取全局选项失败: %w", err)
+	}
+	state := meteredState{enabled: true, downloadLimit: options["max-overall-download-limit"]}
+	if state.downloadLimit == "" {
+		state.downloadLimit = "0"
+	}
+
+	if limit := a.cfg.meteredLimit; limit > 0 {
+		if err := a.ChangeGlobalOption(map[string]string{
+			"max-overall-download-limit": strconv.FormatInt(limit, 10),
+		}); err != nil {
+			return err
+		}
+		a.metered = state
+		return nil
+	}
+
+	gids, err := a.unfinishedGIDs()
+	if err != nil {
+		return err
+	}
+	paused, err := a.callEach("aria2.pause", gids)
+	state.paused = paused
+	// 部分任务暂停失败时也进入计费模式，保证之后能恢复已经暂停的任务
+	a.metered = state
+	return err
+}
+
+// leaveMetered 恢复进入计费模式前的状态
+func (a *Aria2) leaveMetered() error {
+	state := a.metered
+	if a.cfg.meteredLimit > 0 {
+		if err := a.ChangeGlobalOption(map[string]string{
+			"max-overall-download-limit": state.downloadLimit,
+		}); err != nil {
+			return err
+		}
+	}
+	_, err := a.callEach("aria2.unpause", state.paused)
+	a.metered = meteredState{}
+	return err
+}
+
+// callEach 通过一次批量调用对每个任务调用 method，返回调用成功的任务
+func (a *Aria2) callEach(method string, gids []string) ([]string, error) {
+	if len(gids) == 0 {
+		return nil, nil
+	}
+	calls := make([]MethodCall, len(gids))
+	for i, gid := range gids {
+		calls[i] = MethodCall{MethodName: method, Params: []interface{}{gid}}
+	}
+	results, err := a.Multicall(calls)
+	if err != nil {
+		return nil, err
+	}
+	var done []string
+	var errs []error
+	for i, result := range results {
+		if result.Error != nil {
+			errs = append(errs, fmt.Errorf("%s %s 失败: %w", method, gids[i], result.Error))
+			continue
+		}
+		done = append(done, gids[i])
+	}
+	return done, errors.Join(errs...)
+}
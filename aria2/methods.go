@@ -0,0 +1,280 @@
+package aria2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// VersionInfo aria2 版本信息
+type VersionInfo struct {
+	Version         string   `json:"version"`         // aria2 版本号
+	EnabledFeatures []string `json:"enabledFeatures"` // 已启用的特性
+}
+
+// GetVersion 获取 aria2 版本信息
+func (a *Aria2) GetVersion() (*VersionInfo, error) {
+	result, err := a.Call("aria2.getVersion", nil)
+	if err != nil {
+		return nil, err
+	}
+	var version VersionInfo
+	if err := json.Unmarshal(result, &version); err != nil {
+		return nil, fmt.Errorf("解析版本信息失败: %w", err)
+	}
+	return &version, nil
+}
+
+// ChangeGlobalOption 动态修改全局选项
+// 本包使用的选项中连接的 aria2 版本不支持的会被跳过并记录到 CompatibilityWarnings；
+// 其他不认识或不能在运行时修改的选项 aria2 会直接忽略，不会返回错误
+func (a *Aria2) ChangeGlobalOption(options map[string]string) error {
+	return a.changeOptions("aria2.changeGlobalOption", "", options)
+}
+
+// GetGlobalOption 获取当前的全局选项
+func (a *Aria2) GetGlobalOption() (map[string]string, error) {
+	result, err := a.Call("aria2.getGlobalOption", nil)
+	if err != nil {
+		return nil, err
+	}
+	var options map[string]string
+	if err := json.Unmarshal(result, &options); err != nil {
+		return nil, fmt.Errorf("解析全局选项失败: %w", err)
+	}
+	return options, nil
+}
+
+// SetDiskCache 运行时调整磁盘缓存大小（字节），0 表示禁用磁盘缓存
+// 适合根据可用内存动态调整，无需重启 aria2c
+func (a *Aria2) SetDiskCache(bytes int) error {
+	if bytes < 0 {
+		return fmt.Errorf("磁盘缓存大小不能为负数: %d", bytes)
+	}
+	if err := a.ChangeGlobalOption(map[string]string{
+		"disk-cache": strconv.Itoa(bytes),
+	}); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.cfg.diskCache = bytes
+	a.mu.Unlock()
+	return nil
+}
+
+// GetSessionInfo 获取 aria2 会话ID
+// 每次 aria2c 启动都会生成新的会话ID，可用于确认当前连接的是哪个实例
+func (a *Aria2) GetSessionInfo() (string, error) {
+	result, err := a.Call("aria2.getSessionInfo", nil)
+	if err != nil {
+		return "", err
+	}
+	var info struct {
+		SessionID string `json:"sessionId"`
+	}
+	if err := json.Unmarshal(result, &info); err != nil {
+		return "", fmt.Errorf("解析会话信息失败: %w", err)
+	}
+	return info.SessionID, nil
+}
+
+// Remove 删除下载任务，正在下载的任务会先停止
+func (a *Aria2) Remove(gid string) error {
+	_, err := a.Call("aria2.remove", []interface{}{gid})
+	return err
+}
+
+// ForceRemove 强制删除下载任务，不等待断开连接等清理操作
+func (a *Aria2) ForceRemove(gid string) error {
+	_, err := a.Call("aria2.forceRemove", []interface{}{gid})
+	return err
+}
+
+// RemoveDownloadResult 从内存中清除已完成、出错或已删除任务的结果
+func (a *Aria2) RemoveDownloadResult(gid string) error {
+	_, err := a.Call("aria2.removeDownloadResult", []interface{}{gid})
+	if err == nil {
+		a.deleteMetadata(gid)
+	}
+	return err
+}
+
+// Pause 暂停下载任务
+func (a *Aria2) Pause(gid string) error {
+	_, err := a.Call("aria2.pause", []interface{}{gid})
+	return err
+}
+
+// Unpause 恢复暂停的下载任务
+func (a *Aria2) Unpause(gid string) error {
+	_, err := a.Call("aria2.unpause", []interface{}{gid})
+	return err
+}
+
+// GetUris 获取任务使用的地址及其状态（used 表示正在使用，waiting 表示等待使用）
+func (a *Aria2) GetUris(gid string) ([]URI, error) {
+	result, err := a.Call("aria2.getUris", []interface{}{gid})
+	if err != nil {
+		return nil, err
+	}
+	var uris []URI
+	if err := json.Unmarshal(result, &uris); err != nil {
+		return nil, fmt.Errorf("解析地址列表失败: %w", err)
+	}
+	return uris, nil
+}
+
+// ChangeUri 修改任务中第 fileIndex 个文件（从 1 开始）的地址：先删除 delUris，再追加 addUris
+// 返回实际删除和添加的地址数量。正在使用的连接不受影响，aria2 建立新连接时才会使用新地址
+func (a *Aria2) ChangeUri(gid string, fileIndex int, delUris, addUris []string) (deleted, added int, err error) {
+	if delUris == nil {
+		delUris = []string{}
+	}
+	if addUris == nil {
+		addUris = []string{}
+	}
+	result, err := a.Call("aria2.changeUri", []interface{}{gid, fileIndex, delUris, addUris})
+	if err != nil {
+		return 0, 0, err
+	}
+	var counts []int
+	if err := json.Unmarshal(result, &counts); err != nil || len(counts) != 2 {
+		return 0, 0, fmt.Errorf("解析修改地址结果失败: %s", result)
+	}
+	return counts[0], counts[1], nil
+}
+
+// GlobalStat 全局统计信息
+type GlobalStat struct {
+	DownloadSpeed   string `json:"downloadSpeed"`   // 全局下载速度（字节/秒）
+	UploadSpeed     string `json:"uploadSpeed"`     // 全局上传速度（字节/秒）
+	NumActive       string `json:"numActive"`       // 正在下载的任务数
+	NumWaiting      string `json:"numWaiting"`      // 等待中的任务数
+	NumStopped      string `json:"numStopped"`      // 已停止的任务数（受 --max-download-result 限制）
+	NumStoppedTotal string `json:"numStoppedTotal"` // 已停止的任务总数
+}
+
+// GetGlobalStat 获取全局统计信息
+func (a *Aria2) GetGlobalStat() (*GlobalStat, error) {
+	result, err := a.Call("aria2.getGlobalStat", nil)
+	if err != nil {
+		return nil, err
+	}
+	var stat GlobalStat
+	if err := json.Unmarshal(result, &stat); err != nil {
+		return nil, fmt.Errorf("解析全局统计信息失败: %w", err)
+	}
+	return &stat, nil
+}
+
+// ChangeOption 动态修改下载任务的选项
+// 不被支持的选项的处理与 ChangeGlobalOption 相同；任务不存在或选项值无效时返回错误
+func (a *Aria2) ChangeOption(gid string, options map[string]string) error {
+	if gid == "" {
+		return fmt.Errorf("GID 不能为空")
+	}
+	return a.changeOptions("aria2.changeOption", gid, options)
+}
+
+// TellActive 获取所有正在下载的任务状态，keys 为空时返回全部字段
+func (a *Aria2) TellActive(keys ...string) ([]DownloadStatus, error) {
+	params := []interface{}{}
+	if len(keys) > 0 {
+		params = append(params, keys)
+	}
+	return a.tellList("aria2.tellActive", params)
+}
+
+// TellWaiting 获取等待中和暂停的任务状态，从 offset 开始最多返回 num 个
+func (a *Aria2) TellWaiting(offset, num int, keys ...string) ([]DownloadStatus, error) {
+	params := []interface{}{offset, num}
+	if len(keys) > 0 {
+		params = append(params, keys)
+	}
+	return a.tellList("aria2.tellWaiting", params)
+}
+
+// TellStopped 获取已完成、出错和已删除的任务状态，从 offset 开始最多返回 num 个（offset 0 为最早结束的任务）
+func (a *Aria2) TellStopped(offset, num int, keys ...string) ([]DownloadStatus, error) {
+	params := []interface{}{offset, num}
+	if len(keys) > 0 {
+		params = append(params, keys)
+	}
+	return a.tellList("aria2.tellStopped", params)
+}
+
+// TotalConnections 返回所有正在下载的任务的连接数之和
+// 只调用一次 tellActive 并只请求 gid 和 connections 字段
+func (a *Aria2) TotalConnections() (int, error) {
+	active, err := a.TellActive("gid", "connections")
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, status := range active {
+		n, err := strconv.Atoi(status.Connections)
+		if err != nil {
+			continue
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// tellList 调用返回任务状态列表的方法
+func (a *Aria2) tellList(method string, params []interface{}) ([]DownloadStatus, error) {
+	result, err := a.Call(method, params)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []DownloadStatus
+	if err := json.Unmarshal(result, &statuses); err != nil {
+		return nil, fmt.Errorf("解析任务列表失败: %w", err)
+	}
+	return statuses, nil
+}
+
+// SaveSession 将当前会话保存到 --save-session 指定的文件
+func (a *Aria2) SaveSession() error {
+	_, err := a.Call("aria2.saveSession", nil)
+	return err
+}
+
+// ServerInfo 单个文件正在使用的服务器连接
+type ServerInfo struct {
+	Index   string   `json:"index"`   // 文件序号（从 1 开始）
+	Servers []Server `json:"servers"` // 正在连接的服务器
+}
+
+// Server 服务器连接信息
+type Server struct {
+	URI           string `json:"uri"`           // 原始地址
+	CurrentURI    string `json:"currentUri"`    // 当前实际下载的地址（重定向后可能与 URI 不同）
+	DownloadSpeed string `json:"downloadSpeed"` // 该连接的下载速度（字节/秒）
+}
+
+// GetServers 获取下载任务当前连接的服务器
+// 任务不在下载中（等待、暂停、已结束）时返回空切片
+func (a *Aria2) GetServers(gid string) ([]ServerInfo, error) {
+	result, err := a.Call("aria2.getServers", []interface{}{gid})
+	if err != nil {
+		// 任务不在下载中时 aria2 会返回错误
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) {
+			if status, statusErr := a.TellStatusKeys(gid, []string{"status"}); statusErr == nil && status.Status != "active" {
+				return []ServerInfo{}, nil
+			}
+		}
+		return nil, err
+	}
+	servers := []ServerInfo{}
+	if err := json.Unmarshal(result, &servers); err != nil {
+		return nil, fmt.Errorf("解析服务器信息失败: %w", err)
+	}
+	if servers == nil {
+		servers = []ServerInfo{}
+	}
+	return servers, nil
+}
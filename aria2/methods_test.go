@@ -0,0 +1,18 @@
+package aria2_test
+
+import "testing"
+
+func TestGetGlobalOption(t *testing.T) {
+	a, srv := attach(t, nil)
+
+	options, err := a.GetGlobalOption()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := options["max-concurrent-downloads"]; got != "5" {
+		t.Fatalf("max-concurrent-downloads 为 %q，期望 5", got)
+	}
+	if n := count(srv.Calls(), "aria2.getGlobalOption"); n == 0 {
+		t.Fatal("没有调用 aria2.getGlobalOption")
+	}
+}
@@ -0,0 +1,128 @@
+package aria2
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// WithURISelector 设置任务有多个地址（如 AddUriMirrors）时选择镜像的算法，对应 --uri-selector
+//
+//	feedback 根据以往的下载速度选择最快的镜像（aria2 默认值）
+//	inorder  按地址列表的顺序使用
+//	adaptive 选择最快的镜像，并不时尝试尚未使用或较慢的镜像
+func WithURISelector(mode string) Option {
+	return func(a *Aria2) error {
+		switch mode {
+		case "feedback", "inorder", "adaptive":
+		default:
+			return fmt.Errorf("无效的镜像选择算法: %s", mode)
+		}
+		a.cfg.uriSelector = mode
+		return nil
+	}
+}
+
+// uriSelectorArgs 返回镜像选择算法的启动参数
+func (a *Aria2) uriSelectorArgs() []string {
+	if a.cfg.uriSelector == "" {
+		return nil
+	}
+	return []string{"--uri-selector=" + a.cfg.uriSelector}
+}
+
+// mirrorFallback 慢速时切换镜像的配置
+type mirrorFallback struct {
+	minSpeed int64
+	after    time.Duration
+	mirrors  []string
+}
+
+// WithMirrorFallback 下载速度持续低于 minSpeed（字节/秒）达到 after 后，通过 changeUri 换用 mirrors 中的下一个地址
+// mirrors 为同一文件的所有下载地址（包括添加任务时使用的地址），依次尝试，每个只尝试一次，已经在任务中的地址会跳过；
+// 只有当前地址出现在 mirrors 中的任务才会切换，下载其他文件的任务不受影响。
+// 换用镜像时会删除任务中原有的地址，正在使用的连接不受影响，aria2 建立新连接时使用新地址。
+// 尝试过的镜像记录在 DownloadResult.MirrorsTried 中
+func WithMirrorFallback(minSpeed int, after time.Duration, mirrors []string) Option {
+	return func(a *Aria2) error {
+		if minSpeed <= 0 {
+			return fmt.Errorf("最低速度必须大于0: %d", minSpeed)
+		}
+		if after <= 0 {
+			return fmt.Errorf("持续时间必须大于0: %v", after)
+		}
+		if len(mirrors) == 0 {
+			return fmt.Errorf("镜像列表不能为空")
+		}
+		for _, m := range mirrors {
+			if err := validateURI(m); err != nil {
+				return err
+			}
+		}
+		a.cfg.mirrorFallback = &mirrorFallback{
+			minSpeed: int64(minSpeed),
+			after:    after,
+			mirrors:  append([]string(nil), mirrors...),
+		}
+		return nil
+	}
+}
+
+// mirrorState 单个任务的镜像切换状态
+type mirrorState struct {
+	slowSince time.Time // 速度开始低于下限的时间，零值表示当前速度正常
+	next      int       // 下一个尝试的镜像
+	tried     []string  // 已换用的镜像
+}
+
+// checkMirror 速度持续过低时换用下一个镜像
+func (a *Aria2) checkMirror(w *watch, status *DownloadStatus, now time.Time) {
+	fb := a.cfg.mirrorFallback
+	if fb == nil || status.Status != "active" || status.Verifying() || w.mirror.next >= len(fb.mirrors) {
+		return
+	}
+	if speed, _ := status.Speed(); speed >= fb.minSpeed {
+		w.mirror.slowSince = time.Time{}
+		return
+	}
+	if w.mirror.slowSince.IsZero() {
+		w.mirror.slowSince = now
+		return
+	}
+	if now.Sub(w.mirror.slowSince) < fb.after {
+		return
+	}
+	// 无论切换是否成功都重新计时，避免每次轮询都调用
+	w.mirror.slowSince = now
+
+	uris, err := a.GetUris(w.gid)
+	if err != nil {
+		a.logf("获取任务 %s 的地址失败: %v", w.gid, err)
+		return
+	}
+	current := make([]string, 0, len(uris))
+	inUse := make(map[string]bool, len(uris))
+	for _, u := range uris {
+		current = append(current, u.URI)
+		inUse[u.URI] = true
+	}
+	if !slices.ContainsFunc(fb.mirrors, func(m string) bool { return inUse[m] }) {
+		// 任务下载的不是镜像列表中的文件，之后不再检查
+		w.mirror.next = len(fb.mirrors)
+		return
+	}
+	for w.mirror.next < len(fb.mirrors) {
+		mirror := fb.mirrors[w.mirror.next]
+		w.mirror.next++
+		if inUse[mirror] {
+			continue
+		}
+		if _, _, err := a.ChangeUri(w.gid, 1, current, []string{mirror}); err != nil {
+			a.logf("任务 %s 换用镜像失败: %v", w.gid, err)
+			return
+		}
+		w.mirror.tried = append(w.mirror.tried, mirror)
+		a.logf("任务 %s 速度过低，换用镜像 %s", w.gid, RedactURL(mirror))
+		return
+	}
+}
@@ -0,0 +1,49 @@
+package aria2_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dxcweb/go-aria2/aria2"
+	"github.com/dxcweb/go-aria2/aria2/aria2test"
+)
+
+// slowScript 一直以 0 速度下载，若干次查询后完成
+func slowScript(uris []string, options map[string]interface{}) []aria2.DownloadStatus {
+	steps := make([]aria2.DownloadStatus, 30)
+	for i := range steps {
+		steps[i] = aria2.DownloadStatus{Status: "active", TotalLength: "1024", CompletedLength: "0", DownloadSpeed: "0"}
+	}
+	return append(steps, aria2.DownloadStatus{Status: "complete", TotalLength: "1024", CompletedLength: "1024"})
+}
+
+func TestMirrorFallback(t *testing.T) {
+	mirrors := []string{"http://a.example.com/file.bin", "http://b.example.com/file.bin"}
+	tests := []struct {
+		name string
+		url  string
+		want []string
+	}{
+		{name: "镜像列表中的文件", url: mirrors[0], want: mirrors[1:]},
+		{name: "其他文件", url: "http://other.example.com/other.bin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := aria2test.NewClock(time.Unix(0, 0))
+			a, srv := attachWithClock(t, slowScript, clock, aria2.WithMirrorFallback(1024, time.Second, mirrors))
+			runClock(t, clock)
+
+			result := a.DownloadDetailed(tt.url, aria2.DownloadOptions{Dir: t.TempDir()}, nil)
+			if result.Error != nil {
+				t.Fatal(result.Error)
+			}
+			if !reflect.DeepEqual(result.MirrorsTried, tt.want) {
+				t.Fatalf("尝试过的镜像为 %v，期望 %v", result.MirrorsTried, tt.want)
+			}
+			if n := count(srv.Calls(), "aria2.changeUri"); n != len(tt.want) {
+				t.Fatalf("changeUri 调用了 %d 次，期望 %d 次", n, len(tt.want))
+			}
+		})
+	}
+}
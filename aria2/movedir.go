@@ -0,0 +1,146 @@
+package aria2
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrDirChangeRejected aria2 拒绝修改任务的下载目录（通常是任务正在下载），
+// 正在下载的任务可以改用 MoveTaskDir
+var ErrDirChangeRejected = errors.New("aria2 拒绝修改下载目录")
+
+// pauseTimeout MoveTaskDir 等待任务暂停的最长时间
+const pauseTimeout = 30 * time.Second
+
+// ChangeDir 修改尚未开始（等待中或已暂停）的任务的下载目录
+// 只修改 aria2 中的设置，不移动已下载的部分文件；任务正在下载、aria2 拒绝修改时返回包装了 ErrDirChangeRejected 的错误
+func (a *Aria2) ChangeDir(gid, newDir string) error {
+	if newDir == "" {
+		return fmt.Errorf("下载目录不能为空")
+	}
+	dir, err := a.ResolveDir(newDir)
+	if err != nil {
+		return err
+	}
+	if err := a.ChangeOption(gid, map[string]string{"dir": dir}); err != nil {
+		// 只有任务存在且正在下载时才是 aria2 拒绝修改，任务不存在等其他错误原样返回
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) {
+			status, statusErr := a.TellStatusKeys(gid, []string{"status"})
+			if statusErr == nil && status.Status == "active" {
+				return fmt.Errorf("%w: %w", ErrDirChangeRejected, err)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// MoveTaskDir 将任务（包括正在下载的任务）移动到新的下载目录
+// 依次执行：暂停任务并等待暂停完成 → 将已下载的部分文件和 .aria2 控制文件移动到新目录 →
+// 修改下载目录 → 恢复任务，之后从新目录继续下载。
+// 任务原本处于等待或暂停状态时不会暂停和恢复。任一步骤失败时已移动的文件会移回原目录，
+// 之前由本函数暂停的任务会被恢复。新目录需要与原目录在同一个文件系统上
+func (a *Aria2) MoveTaskDir(gid, newDir string) error {
+	if newDir == "" {
+		return fmt.Errorf("下载目录不能为空")
+	}
+	dir, err := a.ResolveDir(newDir)
+	if err != nil {
+		return err
+	}
+	status, err := a.TellStatusKeys(gid, []string{"gid", "status", "dir", "files"})
+	if err != nil {
+		return err
+	}
+	switch status.Status {
+	case "complete", "error", "removed":
+		return fmt.Errorf("任务已结束，无法移动: %s", status.Status)
+	}
+
+	if status.Status == "active" {
+		if err := a.Pause(gid); err != nil {
+			return err
+		}
+		defer a.Unpause(gid)
+		if status, err = a.waitPaused(gid); err != nil {
+			return err
+		}
+	}
+
+	moved, err := movePartialFiles(status, dir)
+	if err == nil {
+		err = a.ChangeDir(gid, dir)
+	}
+	if err != nil {
+		for i := len(moved) - 1; i >= 0; i-- {
+			os.Rename(moved[i][1], moved[i][0])
+		}
+		return err
+	}
+	return nil
+}
+
+// waitPaused 等待任务进入暂停状态，返回暂停后的状态
+func (a *Aria2) waitPaused(gid string) (*DownloadStatus, error) {
+	ticker := a.clock().NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := a.clock().After(pauseTimeout)
+	for {
+		status, err := a.TellStatusKeys(gid, []string{"gid", "status", "dir", "files"})
+		if err != nil {
+			return nil, err
+		}
+		if status.Status != "active" {
+			if status.Status != "paused" {
+				return nil, fmt.Errorf("任务在暂停前已结束: %s", status.Status)
+			}
+			return status, nil
+		}
+		select {
+		case <-ticker.C():
+		case <-timeout:
+			return nil, fmt.Errorf("等待任务暂停超时")
+		case <-a.ctx.Done():
+			return nil, fmt.Errorf("ctx上下文已取消")
+		}
+	}
+}
+
+// movePartialFiles 将任务已下载的文件和 .aria2 控制文件移动到 newDir，保持相对原下载目录的路径
+// 返回已移动的文件（原路径、新路径），出错时由调用方移回
+func movePartialFiles(status *DownloadStatus, newDir string) ([][2]string, error) {
+	var moved [][2]string
+	if status.Dir == "" || filepath.Clean(status.Dir) == filepath.Clean(newDir) {
+		return nil, nil
+	}
+	for _, file := range status.Files {
+		if file.Path == "" {
+			continue
+		}
+		rel, err := filepath.Rel(status.Dir, file.Path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			// 文件不在下载目录中（例如 out 为绝对路径），保持原位置
+			continue
+		}
+		for _, suffix := range []string{"", ".aria2"} {
+			from := file.Path + suffix
+			to := filepath.Join(newDir, rel) + suffix
+			if _, err := os.Stat(from); err != nil {
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+				return moved, fmt.Errorf("无法创建目录: %w", err)
+			}
+			if err := os.Rename(from, to); err != nil {
+				return moved, fmt.Errorf("移动文件失败: %w", err)
+			}
+			moved = append(moved, [2]string{from, to})
+		}
+	}
+	return moved, nil
+}
@@ -0,0 +1,47 @@
+package aria2_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dxcweb/go-aria2/aria2"
+)
+
+func TestChangeDirErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       error // tellStatus 返回的错误，nil 表示任务正在下载
+		wantRejected bool
+	}{
+		{name: "任务正在下载", wantRejected: true},
+		{name: "任务不存在", status: &aria2.RPCError{Code: 1, Message: "GID 2089b05ecca3d829 is not found"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := newRPCServer(t, func(req rpcRequest) (interface{}, error) {
+				switch req.Method {
+				case "aria2.changeOption":
+					return nil, &aria2.RPCError{Code: 1, Message: "changeOption failed"}
+				case "aria2.tellStatus":
+					if tt.status != nil {
+						return nil, tt.status
+					}
+					return map[string]string{"gid": "2089b05ecca3d829", "status": "active"}, nil
+				}
+				return "OK", nil
+			})
+			a, err := aria2.NewAria2(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = a.ChangeDir("2089b05ecca3d829", t.TempDir())
+			if err == nil {
+				t.Fatal("aria2 返回错误时 ChangeDir 应返回错误")
+			}
+			if got := errors.Is(err, aria2.ErrDirChangeRejected); got != tt.wantRejected {
+				t.Fatalf("errors.Is(err, ErrDirChangeRejected) = %v，期望 %v: %v", got, tt.wantRejected, err)
+			}
+		})
+	}
+}
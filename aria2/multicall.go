@@ -0,0 +1,61 @@
+package aria2
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MethodCall system.multicall 中的单个方法调用
+type MethodCall struct {
+	MethodName string        `json:"methodName"`
+	Params     []interface{} `json:"params"`
+}
+
+// MulticallResult system.multicall 中单个方法调用的结果
+type MulticallResult struct {
+	Result json.RawMessage // 调用成功时的返回值
+	Error  error           // 调用失败时的错误
+}
+
+// Multicall 在一次请求中执行多个方法调用
+// 返回的结果与 calls 一一对应，单个调用失败不影响其他调用
+func (a *Aria2) Multicall(calls []MethodCall) ([]MulticallResult, error) {
+	// 复制调用再添加 token，不修改调用方的 calls，重复使用同一个切片时 token 不会重复添加
+	withTokens := make([]MethodCall, len(calls))
+	for i, call := range calls {
+		params := call.Params
+		if params == nil {
+			params = []interface{}{}
+		}
+		withTokens[i] = MethodCall{MethodName: call.MethodName, Params: a.withToken(call.MethodName, params)}
+	}
+	result, err := a.Call("system.multicall", []interface{}{withTokens})
+	if err != nil {
+		return nil, err
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(result, &items); err != nil {
+		return nil, fmt.Errorf("解析批量调用结果失败: %w", err)
+	}
+	if len(items) != len(calls) {
+		return nil, fmt.Errorf("批量调用结果数量不匹配: 请求%d个, 返回%d个", len(calls), len(items))
+	}
+
+	// 成功的调用返回 [result]，失败的调用返回 {code, message}
+	results := make([]MulticallResult, len(items))
+	for i, item := range items {
+		var values []json.RawMessage
+		if err := json.Unmarshal(item, &values); err == nil && len(values) == 1 {
+			results[i].Result = values[0]
+			continue
+		}
+		var rpcErr RPCError
+		if err := json.Unmarshal(item, &rpcErr); err != nil {
+			results[i].Error = fmt.Errorf("解析批量调用结果失败: %w", err)
+			continue
+		}
+		results[i].Error = &rpcErr
+	}
+	return results, nil
+}
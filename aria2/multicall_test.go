@@ -0,0 +1,75 @@
+package aria2_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dxcweb/go-aria2/aria2"
+)
+
+func TestMulticallDoesNotMutateCalls(t *testing.T) {
+	var tokens []int
+	opts := newRPCServer(t, func(req rpcRequest) (interface{}, error) {
+		var calls []struct {
+			MethodName string        `json:"methodName"`
+			Params     []interface{} `json:"params"`
+		}
+		if err := json.Unmarshal(req.Params[0], &calls); err != nil {
+			return nil, &aria2.RPCError{Code: 1, Message: err.Error()}
+		}
+		results := make([]interface{}, len(calls))
+		for i, call := range calls {
+			n := 0
+			for _, p := range call.Params {
+				if p == "token:secret" {
+					n++
+				}
+			}
+			tokens = append(tokens, n)
+			results[i] = []interface{}{"OK"}
+		}
+		return results, nil
+	})
+	a, err := aria2.NewAria2(append(opts, aria2.WithSecret("secret"))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := []aria2.MethodCall{{MethodName: "aria2.pause", Params: []interface{}{"2089b05ecca3d829"}}}
+	for i := 0; i < 2; i++ {
+		if _, err := a.Multicall(calls); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(calls[0].Params) != 1 {
+		t.Fatalf("调用方的参数被修改为 %v", calls[0].Params)
+	}
+	for i, n := range tokens {
+		if n != 1 {
+			t.Fatalf("第 %d 次请求中 token 出现了 %d 次", i+1, n)
+		}
+	}
+}
+
+func TestMulticallPerCallErrors(t *testing.T) {
+	a, _ := attach(t, nil)
+
+	results, err := a.Multicall([]aria2.MethodCall{
+		{MethodName: "aria2.getVersion"},
+		{MethodName: "aria2.tellStatus", Params: []interface{}{"ffffffffffffffff"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("返回 %d 个结果，期望 2 个", len(results))
+	}
+	if results[0].Error != nil || len(results[0].Result) == 0 {
+		t.Fatalf("第一个调用应成功: %+v", results[0])
+	}
+	var rpcErr *aria2.RPCError
+	if !errors.As(results[1].Error, &rpcErr) {
+		t.Fatalf("第二个调用的错误为 %v，期望 *aria2.RPCError", results[1].Error)
+	}
+}
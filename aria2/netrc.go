@@ -0,0 +1,50 @@
+package aria2
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// WithNetrc 使用 netrc 文件中的账号密码进行 HTTP/FTP 认证，对应 --netrc-path
+// 文件必须存在且可读；aria2 会忽略权限不是 600 的 netrc 文件，因此除 Windows 外
+// 文件对属主以外的用户有任何权限时返回错误
+func WithNetrc(path string) Option {
+	return func(a *Aria2) error {
+		if err := checkNetrc(path); err != nil {
+			return err
+		}
+		a.cfg.netrcPath = path
+		return nil
+	}
+}
+
+// checkNetrc 检查 netrc 文件是否存在、可读且只有属主有权限
+func checkNetrc(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("无法读取 netrc 文件: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("无法读取 netrc 文件: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("netrc 路径是目录: %s", path)
+	}
+	// Windows 上权限位没有意义
+	if perm := info.Mode().Perm(); runtime.GOOS != "windows" && perm&0077 != 0 {
+		return fmt.Errorf("netrc 文件 %s 的权限为 %o，aria2 会忽略它，请执行 chmod 600", path, perm)
+	}
+	return nil
+}
+
+// prepareNetrc 启动前再次检查 netrc 文件
+func (a *Aria2) prepareNetrc() error {
+	if a.cfg.netrcPath == "" {
+		return nil
+	}
+	return checkNetrc(a.cfg.netrcPath)
+}
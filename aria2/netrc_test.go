@@ -0,0 +1,67 @@
+package aria2
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestWithNetrcAddsFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "netrc")
+	if err := os.WriteFile(path, []byte("machine example.com login user password pass\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	a := newInstance(t, WithNetrc(path))
+
+	args := a.buildArgs()
+	for _, want := range []string{"--no-netrc=false", "--netrc-path=" + path} {
+		if !hasArg(args, want) {
+			t.Errorf("参数中缺少 %s: %v", want, args)
+		}
+	}
+}
+
+func TestWithNetrcMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing")
+	if _, err := NewAria2(WithNetrc(path)); err == nil {
+		t.Fatal("netrc 文件不存在时 NewAria2 应返回错误")
+	}
+}
+
+func TestWithNetrcRejectsGroupReadable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows 上不检查权限位")
+	}
+	for _, perm := range []os.FileMode{0640, 0660, 0644} {
+		path := filepath.Join(t.TempDir(), "netrc")
+		if err := os.WriteFile(path, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(path, perm); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewAria2(WithNetrc(path)); err == nil {
+			t.Errorf("netrc 文件权限为 %o 时 NewAria2 应返回错误", perm)
+		}
+	}
+}
+
+func TestSpawnFailsWhenNetrcRemoved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "netrc")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	a := newInstance(t, WithNetrc(path))
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Spawn(); err == nil {
+		a.Stop()
+		t.Fatal("netrc 文件已删除时 Spawn 应返回错误")
+	}
+	if a.cmd != nil {
+		t.Fatal("netrc 检查失败后不应启动 aria2c")
+	}
+}
@@ -0,0 +1,297 @@
+package aria2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EventType 通知事件类型
+type EventType int
+
+const (
+	// EventConnected 通知连接已建立（包括重连成功）
+	EventConnected EventType = iota
+	// EventDisconnected 通知连接已断开，之后会自动重连
+	EventDisconnected
+	// EventDownloadStart 任务开始下载
+	EventDownloadStart
+	// EventDownloadPause 任务已暂停
+	EventDownloadPause
+	// EventDownloadStop 任务被用户停止
+	EventDownloadStop
+	// EventDownloadComplete 任务下载完成
+	EventDownloadComplete
+	// EventDownloadError 任务出错停止
+	EventDownloadError
+	// EventBtDownloadComplete BT 任务下载完成（做种中）
+	EventBtDownloadComplete
+	// EventGlobalStat 定时推送的全局统计，见 WithGlobalStatInterval
+	EventGlobalStat
+)
+
+// notificationEvents aria2 通知方法名与事件类型的对应关系
+var notificationEvents = map[string]EventType{
+	"aria2.onDownloadStart":      EventDownloadStart,
+	"aria2.onDownloadPause":      EventDownloadPause,
+	"aria2.onDownloadStop":       EventDownloadStop,
+	"aria2.onDownloadComplete":   EventDownloadComplete,
+	"aria2.onDownloadError":      EventDownloadError,
+	"aria2.onBtDownloadComplete": EventBtDownloadComplete,
+}
+
+// String 返回事件类型名称
+func (t EventType) String() string {
+	switch t {
+	case EventConnected:
+		return "connected"
+	case EventDisconnected:
+		return "disconnected"
+	case EventDownloadStart:
+		return "start"
+	case EventDownloadPause:
+		return "pause"
+	case EventDownloadStop:
+		return "stop"
+	case EventDownloadComplete:
+		return "complete"
+	case EventDownloadError:
+		return "error"
+	case EventBtDownloadComplete:
+		return "bt-complete"
+	case EventGlobalStat:
+		return "global-stat"
+	}
+	return fmt.Sprintf("EventType(%d)", int(t))
+}
+
+// status 返回任务事件对应的任务状态，BT 任务下载完成后仍在做种，状态为 StatusActive
+func (t EventType) status() Status {
+	switch t {
+	case EventDownloadStart, EventBtDownloadComplete:
+		return StatusActive
+	case EventDownloadPause:
+		return StatusPaused
+	case EventDownloadStop:
+		return StatusRemoved
+	case EventDownloadComplete:
+		return StatusComplete
+	case EventDownloadError:
+		return StatusError
+	}
+	return StatusUnknown
+}
+
+// Event 通知事件
+type Event struct {
+	Type     EventType
+	GID      string // 任务 GID，连接状态事件为空
+	Err      error  // 断开连接的原因，仅 EventDisconnected 有效
+	Resynced bool   // 事件由重连后查询任务状态补发，而不是 aria2 推送的
+	Status   Status // 事件对应的任务状态，连接状态和全局统计事件为 StatusUnknown
+
+	GlobalStat *GlobalStat // 全局统计，仅 EventGlobalStat 有效
+}
+
+// 重连退避时间
+const (
+	reconnectMinDelay = 500 * time.Millisecond
+	reconnectMaxDelay = 30 * time.Second
+)
+
+// notification aria2 推送的通知消息
+type notification struct {
+	Method string `json:"method"`
+	Params []struct {
+		GID string `json:"gid"`
+	} `json:"params"`
+}
+
+// subscriber WebSocket 通知订阅
+type subscriber struct {
+	aria2   *Aria2
+	events  chan Event
+	tracked map[string]bool // 尚未结束的任务
+}
+
+// Subscribe 通过 WebSocket 订阅 aria2 的任务通知，直到 ctx 取消时关闭返回的 channel
+// 连接断开后会按退避时间自动重连，重连后通过 tellActive/tellWaiting 重新查询之前跟踪的任务，
+// 断开期间结束的任务会补发对应事件（Resynced 为 true），不会遗漏完成事件
+func (a *Aria2) Subscribe(ctx context.Context) (<-chan Event, error) {
+	a.mu.Lock()
+	running := a.running
+	a.mu.Unlock()
+	if !running {
+		return nil, fmt.Errorf("aria2 未运行")
+	}
+
+	s := &subscriber{
+		aria2:   a,
+		events:  make(chan Event, 64),
+		tracked: make(map[string]bool),
+	}
+	go s.run(ctx)
+	return s.events, nil
+}
+
+// run 连接并读取通知，断开后重连
+func (s *subscriber) run(ctx context.Context) {
+	defer close(s.events)
+	// 全局统计协程也会发送事件，必须在关闭 channel 之前退出
+	defer s.startGlobalStats(ctx)()
+
+	url := s.aria2.rpcURL("ws")
+	delay := reconnectMinDelay
+	connected := false
+	for {
+		conn, err := dialWebSocket(ctx, url, s.aria2.cfg.rpcTLS)
+		if err == nil {
+			delay = reconnectMinDelay
+			if !s.emit(ctx, Event{Type: EventConnected}) {
+				conn.Close()
+				return
+			}
+			// 先建立连接再查询，查询期间推送的通知会缓存在连接中
+			s.resync(ctx, connected)
+			connected = true
+			err = s.read(ctx, conn)
+			conn.Close()
+			if ctx.Err() != nil {
+				return
+			}
+			if !s.emit(ctx, Event{Type: EventDisconnected, Err: err}) {
+				return
+			}
+		} else if ctx.Err() != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.aria2.clock().After(delay):
+		}
+		delay *= 2
+		if delay > reconnectMaxDelay {
+			delay = reconnectMaxDelay
+		}
+	}
+}
+
+// read 读取通知直到连接断开
+func (s *subscriber) read(ctx context.Context, conn *wsConn) error {
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	for {
+		data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		var n notification
+		if err := json.Unmarshal(data, &n); err != nil {
+			continue
+		}
+		eventType, ok := notificationEvents[n.Method]
+		if !ok {
+			// 不是通知（例如 RPC 响应）
+			continue
+		}
+		for _, p := range n.Params {
+			s.track(eventType, p.GID)
+			if !s.emit(ctx, Event{Type: eventType, GID: p.GID, Status: eventType.status()}) {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// track 根据事件更新跟踪的任务
+func (s *subscriber) track(eventType EventType, gid string) {
+	switch eventType {
+	case EventDownloadStop, EventDownloadComplete, EventDownloadError:
+		delete(s.tracked, gid)
+	default:
+		s.tracked[gid] = true
+	}
+}
+
+// resync 查询当前未结束的任务；重连时为断开期间已结束的任务补发事件
+func (s *subscriber) resync(ctx context.Context, reconnect bool) {
+	a := s.aria2
+	present := make(map[string]bool)
+	active, err := a.TellActive("gid")
+	if err != nil {
+		a.logf("重新查询活动任务失败: %v", err)
+		return
+	}
+	for _, st := range active {
+		present[st.GID] = true
+	}
+	// 分页获取所有等待中的任务，超出一页的任务不能当作已结束
+	for offset := 0; ; offset += waitingPageSize {
+		waiting, err := a.TellWaiting(offset, waitingPageSize, "gid")
+		if err != nil {
+			a.logf("重新查询等待任务失败: %v", err)
+			return
+		}
+		for _, st := range waiting {
+			present[st.GID] = true
+		}
+		if len(waiting) < waitingPageSize {
+			break
+		}
+	}
+
+	if reconnect {
+		for gid := range s.tracked {
+			if present[gid] {
+				continue
+			}
+			status, err := a.TellStatusKeys(gid, []string{"gid", "status"})
+			if err != nil {
+				// 任务结果已被清除，无法确定最终状态
+				a.logf("查询任务 %s 状态失败: %v", gid, err)
+				delete(s.tracked, gid)
+				continue
+			}
+			eventType, ok := statusEvent(status.Status)
+			if !ok {
+				continue
+			}
+			s.track(eventType, gid)
+			if !s.emit(ctx, Event{Type: eventType, GID: gid, Resynced: true, Status: status.State()}) {
+				return
+			}
+		}
+	}
+	for gid := range present {
+		s.tracked[gid] = true
+	}
+}
+
+// statusEvent 返回任务状态对应的事件类型
+func statusEvent(status string) (EventType, bool) {
+	switch status {
+	case "complete":
+		return EventDownloadComplete, true
+	case "error":
+		return EventDownloadError, true
+	case "removed":
+		return EventDownloadStop, true
+	case "paused":
+		return EventDownloadPause, true
+	}
+	return 0, false
+}
+
+// emit 发送事件，ctx 取消时返回 false
+func (s *subscriber) emit(ctx context.Context, event Event) bool {
+	select {
+	case s.events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
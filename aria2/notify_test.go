@@ -0,0 +1,103 @@
+package aria2_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dxcweb/go-aria2/aria2"
+	"github.com/dxcweb/go-aria2/aria2/aria2test"
+)
+
+func TestSubscribeReceivesNotifications(t *testing.T) {
+	a, srv := attach(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := a.Subscribe(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := nextEvent(t, events); e.Type != aria2.EventConnected {
+		t.Fatalf("第一个事件为 %v，期望 connected", e.Type)
+	}
+
+	srv.Notify("aria2.onDownloadComplete", "2089b05ecca3d829")
+	e := nextEvent(t, events)
+	if e.Type != aria2.EventDownloadComplete || e.GID != "2089b05ecca3d829" || e.Status != aria2.StatusComplete {
+		t.Fatalf("收到事件 %+v，期望任务 2089b05ecca3d829 的 complete 事件", e)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+// nextEvent 读取下一个事件，超时则测试失败
+func nextEvent(t *testing.T, events <-chan aria2.Event) aria2.Event {
+	t.Helper()
+	select {
+	case e, ok := <-events:
+		if !ok {
+			t.Fatal("事件 channel 已关闭")
+		}
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("等待事件超时")
+	}
+	return aria2.Event{}
+}
+
+func TestSubscribeResyncPagesWaitingTasks(t *testing.T) {
+	waiting := func(uris []string, options map[string]interface{}) []aria2.DownloadStatus {
+		return []aria2.DownloadStatus{{Status: "waiting"}}
+	}
+	clock := aria2test.NewClock(time.Unix(0, 0))
+	a, srv := attachWithClock(t, waiting, clock, aria2.WithGlobalStatInterval(0))
+	runClock(t, clock)
+
+	// 超过一页的等待任务，最后一个任务只出现在第二页
+	var last string
+	for i := 0; i < 1001; i++ {
+		gid, err := a.AddUri("http://example.com/file.bin", t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		last = gid
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := a.Subscribe(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := nextEvent(t, events); e.Type != aria2.EventConnected {
+		t.Fatalf("第一个事件为 %v，期望 connected", e.Type)
+	}
+	srv.Notify("aria2.onDownloadStart", last)
+	if e := nextEvent(t, events); e.Type != aria2.EventDownloadStart || e.GID != last {
+		t.Fatalf("收到事件 %+v，期望任务 %s 的 start 事件", e, last)
+	}
+
+	srv.DropConnections()
+	if e := nextEvent(t, events); e.Type != aria2.EventDisconnected {
+		t.Fatalf("断开后收到事件 %v，期望 disconnected", e.Type)
+	}
+	if e := nextEvent(t, events); e.Type != aria2.EventConnected {
+		t.Fatalf("重连后收到事件 %v，期望 connected", e.Type)
+	}
+	before := count(srv.Calls(), "aria2.tellStatus")
+	// 重新查询结束后才会读取通知，收到这个事件说明重新查询已经完成
+	srv.Notify("aria2.onDownloadStart", last)
+	if e := nextEvent(t, events); e.Type != aria2.EventDownloadStart || e.Resynced {
+		t.Fatalf("重连后收到事件 %+v，第二页中的任务不应被当作已结束", e)
+	}
+	if n := count(srv.Calls(), "aria2.tellStatus") - before; n != 0 {
+		t.Fatalf("重连后调用了 %d 次 tellStatus，第二页中的任务不应被当作已结束", n)
+	}
+
+	cancel()
+	for range events {
+	}
+}
@@ -0,0 +1,288 @@
+package aria2
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"runtime"
+	"time"
+)
+
+// Option 创建 Aria2 实例时使用的配置项
+type Option func(*Aria2) error
+
+// config aria2c 启动配置
+type config struct {
+	diskCache              int                 // 磁盘缓存大小（字节），0 表示禁用
+	onStart                StartCallback       // 下载开始回调
+	btListenPortStart      int                 // BitTorrent 监听端口范围起始，0 表示使用 aria2 默认值
+	btListenPortEnd        int                 // BitTorrent 监听端口范围结束
+	btMaxPeers             int                 // 每个种子的最大连接节点数，-1 表示使用 aria2 默认值
+	maxOverallUploadLimit  int                 // 全局最大上传速度（字节/秒），0 表示不限制
+	autoSaveInterval       int                 // 控制文件自动保存间隔（秒），-1 表示使用 aria2 默认值
+	defaultDir             string              // 默认下载目录，为空时使用系统的“下载”目录
+	allowPieceLengthChange bool                // 分片大小与控制文件不一致时是否继续下载
+	resumeFailurePolicy    ResumeFailurePolicy // 断点续传失败时的处理策略
+	secret                 string              // RPC 密钥
+	maxConcurrentDownloads int                 // 最大同时下载任务数，0 表示使用 aria2 默认值
+	dataDir                string              // aria2c 二进制文件的提取目录，为空时使用系统应用数据目录
+	saveSession            string              // 会话文件路径
+	outputTemplate         OutputTemplate      // 批量下载的输出文件名模板
+	fileAllocation         string              // 文件预分配方式
+	logger                 *log.Logger         // 日志输出
+	netrcPath              string              // netrc 文件路径
+	onProgress             ProgressCallback    // 增量进度回调
+	diskPool               *diskPool           // 磁盘池
+	filenameSanitizer      func(string) string // 文件名清理函数
+	maxTotalConnections    int                 // 所有任务的连接总数上限，0 表示不限制
+	iface                  string              // 下载使用的网络接口
+	multipleInterfaces     []string            // 下载使用的多个网络接口
+	pollInterval           time.Duration       // 任务状态查询间隔，0 表示默认 1 秒
+	callbackThrottle       time.Duration       // 两次回调的最小间隔，0 表示不限制
+	remoteBinaryURL        string              // 下载 aria2c 二进制文件的地址
+	remoteBinarySHA256     string              // 下载的二进制文件的 SHA-256
+	removeArchive          bool                // 解压后删除压缩包
+	minFileSize            int64               // 下载完成的文件的最小大小
+	expectedContentTypes   []string            // 下载完成的文件允许的内容类型
+	deleteUnexpected       bool                // 删除不符合预期的文件
+	logFile                string              // aria2 日志文件路径
+	checkIntegrity         bool                // 添加任务时校验已有文件
+	ipVersion              IPVersion           // 下载使用的 IP 协议族
+	portFixed              bool                // 是否通过 WithPort 指定了端口
+
+	onStatusChange StatusChangeCallback // 任务状态变化回调
+	conditionalGet bool                 // 只在远程文件更新时下载
+
+	ephemeralBinary bool          // 将 aria2c 提取到临时目录，退出后删除
+	rpcCertFile     string        // RPC 服务证书
+	rpcKeyFile      string        // RPC 服务私钥
+	rpcTLS          *tls.Config   // 访问 RPC 使用的 TLS 配置，nil 表示使用明文
+	rpcInsecure     bool          // 不校验 RPC 服务端证书
+	meteredLimit    int64         // 计费网络下的下载速度上限，0 表示暂停所有任务
+	maxTries        int           // 默认最大尝试次数，-1 表示使用 aria2 默认值
+	retryWait       time.Duration // 默认重试间隔，0 表示使用 aria2 默认值
+	pieceSelector   string        // 分片选择算法，为空时使用 aria2 默认值
+	clock           Clock         // 时间来源，nil 表示使用系统时间
+
+	globalStatInterval time.Duration // Subscribe 推送全局统计的间隔，0 表示不推送
+	autoSplit          bool          // 按文件大小自动调整分段数
+	saveSessionEvery   time.Duration // aria2 自动保存会话的间隔
+	flushSessionEvery  time.Duration // 本包定时调用 SaveSession 的间隔
+	failFast           bool          // HTTP 错误时不重试
+
+	collisionStrategy CollisionStrategy // 批量下载时重复文件名的处理方式
+	onStartScript     string            // 任务开始时执行的脚本
+	onCompleteScript  string            // 任务完成时执行的脚本
+	onErrorScript     string            // 任务出错时执行的脚本
+	maxFileSize       int64             // 单个任务的最大文件大小，0 表示不限制
+	mirrorFallback    *mirrorFallback   // 慢速时切换镜像，nil 表示不切换
+	seedUnverified    bool              // 做种前不校验已下载的文件
+	literalPaths      bool              // 不展开下载目录中的 ~ 和环境变量
+	logLevel          string            // 日志文件的级别，为空时使用 error
+	priority          Priority          // aria2c 进程的调度优先级
+	asyncDNS          *bool             // 是否使用异步 DNS，nil 表示使用 aria2 默认值
+	dnsServers        []string          // 异步 DNS 使用的服务器
+	uriSelector       string            // 镜像选择算法，为空时使用 aria2 默认值 feedback
+	maxConcurrentRPC  int               // 同时进行的 RPC 请求数上限，0 表示默认 16
+	skipCertCheck     bool              // 下载时不校验服务器证书
+	caCertificate     string            // 校验下载服务器证书使用的 CA
+	clientCert        string            // 下载时使用的客户端证书
+	clientKey         string            // 客户端证书的私钥
+}
+
+// defaultConfig 默认启动配置
+func defaultConfig() config {
+	return config{
+		diskCache:        64 * 1024 * 1024,
+		btMaxPeers:       -1,
+		autoSaveInterval: -1,
+		fileAllocation:   defaultFileAllocation(),
+		maxTries:         -1,
+
+		globalStatInterval: defaultGlobalStatInterval,
+	}
+}
+
+// defaultFileAllocation 默认的文件预分配方式
+// Linux 上主流文件系统都支持 fallocate，其他平台使用 none 避免大文件预分配时长时间卡住
+func defaultFileAllocation() string {
+	if runtime.GOOS == "linux" {
+		return "falloc"
+	}
+	return "none"
+}
+
+// NewAria2 创建一个新的 Aria2 实例
+func NewAria2(opts ...Option) (*Aria2, error) {
+	a := newDaemon()
+	for _, opt := range opts {
+		if err := opt(a); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// WithDiskCache 设置磁盘缓存大小（字节），0 表示禁用磁盘缓存
+func WithDiskCache(bytes int) Option {
+	return func(a *Aria2) error {
+		if bytes < 0 {
+			return fmt.Errorf("磁盘缓存大小不能为负数: %d", bytes)
+		}
+		a.cfg.diskCache = bytes
+		return nil
+	}
+}
+
+// WithOnStart 设置下载开始回调
+// 任务进入 active 状态且文件名和总大小已知时调用，每个任务只调用一次
+func WithOnStart(callback StartCallback) Option {
+	return func(a *Aria2) error {
+		a.cfg.onStart = callback
+		return nil
+	}
+}
+
+// WithBtListenPort 设置 BitTorrent 监听端口范围
+// 启动时在范围内选择第一个可用端口
+func WithBtListenPort(start, end int) Option {
+	return func(a *Aria2) error {
+		if start < 1 || end > 65535 || start > end {
+			return fmt.Errorf("无效的端口范围: %d-%d", start, end)
+		}
+		a.cfg.btListenPortStart = start
+		a.cfg.btListenPortEnd = end
+		return nil
+	}
+}
+
+// WithMaxPeers 设置每个种子的最大连接节点数，0 表示不限制
+func WithMaxPeers(n int) Option {
+	return func(a *Aria2) error {
+		if n < 0 {
+			return fmt.Errorf("最大节点数不能为负数: %d", n)
+		}
+		a.cfg.btMaxPeers = n
+		return nil
+	}
+}
+
+// WithMaxOverallUploadLimit 设置全局最大上传速度（字节/秒），0 表示不限制
+func WithMaxOverallUploadLimit(bytesPerSec int) Option {
+	return func(a *Aria2) error {
+		if bytesPerSec < 0 {
+			return fmt.Errorf("上传速度限制不能为负数: %d", bytesPerSec)
+		}
+		a.cfg.maxOverallUploadLimit = bytesPerSec
+		return nil
+	}
+}
+
+// WithAutoSaveInterval 设置 .aria2 控制文件的自动保存间隔（秒），范围 0-600，0 表示禁用
+// 间隔越短，进程异常退出时丢失的下载进度越少。
+// 控制文件总是与下载文件保存在同一目录，aria2 不支持单独指定控制文件目录。
+func WithAutoSaveInterval(seconds int) Option {
+	return func(a *Aria2) error {
+		if seconds < 0 || seconds > 600 {
+			return fmt.Errorf("自动保存间隔必须在0-600秒之间: %d", seconds)
+		}
+		a.cfg.autoSaveInterval = seconds
+		return nil
+	}
+}
+
+// WithAllowPieceLengthChange 设置分片大小与控制文件记录不一致时是否继续下载
+// 为 false 时 aria2 会终止下载
+func WithAllowPieceLengthChange(allow bool) Option {
+	return func(a *Aria2) error {
+		a.cfg.allowPieceLengthChange = allow
+		return nil
+	}
+}
+
+// WithResumeFailurePolicy 设置断点续传失败（错误代码 8）时的处理策略
+// 仅对 DownloadWithOptions 等由本包监控的下载生效
+func WithResumeFailurePolicy(policy ResumeFailurePolicy) Option {
+	return func(a *Aria2) error {
+		if policy != FailOnResumeError && policy != RestartFromScratch {
+			return fmt.Errorf("无效的断点续传失败策略: %d", policy)
+		}
+		a.cfg.resumeFailurePolicy = policy
+		return nil
+	}
+}
+
+// WithPort 指定 RPC 端口，不指定时从 6800 开始自动寻找可用端口
+func WithPort(port int) Option {
+	return func(a *Aria2) error {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("无效的端口: %d", port)
+		}
+		releasePort(a.port)
+		a.port = port
+		a.cfg.portFixed = true
+		return nil
+	}
+}
+
+// WithSecret 设置 RPC 密钥，启动时传给 --rpc-secret，每次调用自动附带 token
+func WithSecret(secret string) Option {
+	return func(a *Aria2) error {
+		a.cfg.secret = secret
+		return nil
+	}
+}
+
+// WithMaxConcurrentDownloads 设置最大同时下载任务数
+func WithMaxConcurrentDownloads(n int) Option {
+	return func(a *Aria2) error {
+		if n <= 0 {
+			return fmt.Errorf("最大同时下载任务数必须大于0: %d", n)
+		}
+		a.cfg.maxConcurrentDownloads = n
+		return nil
+	}
+}
+
+// WithDataDir 设置 aria2c 二进制文件的提取目录
+func WithDataDir(dir string) Option {
+	return func(a *Aria2) error {
+		if dir == "" {
+			return fmt.Errorf("数据目录不能为空")
+		}
+		a.cfg.dataDir = dir
+		return nil
+	}
+}
+
+// WithSaveSession 设置会话文件路径
+// aria2c 退出或调用 SaveSession 时将未完成的任务保存到该文件，下次启动时自动恢复
+func WithSaveSession(path string) Option {
+	return func(a *Aria2) error {
+		if path == "" {
+			return fmt.Errorf("会话文件路径不能为空")
+		}
+		a.cfg.saveSession = path
+		return nil
+	}
+}
+
+// WithFileAllocation 设置文件预分配方式，对应 --file-allocation
+//
+//	none     不预分配，开始最快，多线程下载时文件碎片较多
+//	prealloc 写零预分配，在不支持 extent 的文件系统上大文件需要等待很久（aria2 默认值）
+//	trunc    只调整文件大小，速度快但不保证磁盘空间
+//	falloc   使用 fallocate 瞬间完成分配，需要 ext4、btrfs、xfs、NTFS 等文件系统支持
+//
+// 默认在 Linux 上使用 falloc，其他平台使用 none
+func WithFileAllocation(mode string) Option {
+	return func(a *Aria2) error {
+		switch mode {
+		case "none", "prealloc", "trunc", "falloc":
+			a.cfg.fileAllocation = mode
+			return nil
+		default:
+			return fmt.Errorf("无效的文件预分配方式: %s", mode)
+		}
+	}
+}
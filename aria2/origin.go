@@ -0,0 +1,32 @@
+package aria2
+
+// DaemonOrigin aria2c 进程的来源
+type DaemonOrigin int
+
+const (
+	// OriginNone 尚未启动或连接，或者已经停止
+	OriginNone DaemonOrigin = iota
+	// OriginLaunched 由本实例通过 Start/Spawn 启动
+	OriginLaunched
+	// OriginAttached 通过 Attach 连接到已经在运行的 aria2c
+	OriginAttached
+)
+
+// String 返回来源名称
+func (o DaemonOrigin) String() string {
+	switch o {
+	case OriginLaunched:
+		return "launched"
+	case OriginAttached:
+		return "attached"
+	}
+	return "none"
+}
+
+// Origin 返回当前使用的 aria2c 是由本实例启动的还是连接到已有进程的
+// 可用于崩溃重启后判断是否需要恢复会话状态
+func (a *Aria2) Origin() DaemonOrigin {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.origin
+}
@@ -0,0 +1,82 @@
+package aria2
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// WithPathExpansion 设置是否展开下载目录中的 ~ 和环境变量，默认开启
+// 需要使用字面上包含 ~ 或 $ 的目录名时关闭
+func WithPathExpansion(enabled bool) Option {
+	return func(a *Aria2) error {
+		a.cfg.literalPaths = !enabled
+		return nil
+	}
+}
+
+// unixEnvPattern Unix 风格的环境变量引用，如 $HOME 和 ${HOME}
+var unixEnvPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// windowsEnvPattern Windows 风格的环境变量引用，如 %USERPROFILE%
+var windowsEnvPattern = regexp.MustCompile(`%([A-Za-z_][A-Za-z0-9_()]*)%`)
+
+// ExpandPath 展开路径开头的 ~ 和路径中的环境变量
+// "~" 和 "~/..." 展开为当前用户的主目录，"~user/..." 尽量展开为该用户的主目录；
+// $VAR 和 ${VAR} 在所有平台上展开，%VAR% 只在 Windows 上展开。
+// 无法确定的主目录和未设置的环境变量保持原样（包括 ${VAR} 的括号），不会被替换为空字符串
+func ExpandPath(path string) string {
+	path = unixEnvPattern.ReplaceAllStringFunc(path, func(ref string) string {
+		m := unixEnvPattern.FindStringSubmatch(ref)
+		if value, ok := os.LookupEnv(m[1] + m[2]); ok {
+			return value
+		}
+		return ref
+	})
+	if runtime.GOOS == "windows" {
+		path = windowsEnvPattern.ReplaceAllStringFunc(path, func(ref string) string {
+			if value, ok := os.LookupEnv(ref[1 : len(ref)-1]); ok {
+				return value
+			}
+			return ref
+		})
+	}
+	return expandHome(path)
+}
+
+// expandHome 展开路径开头的 ~ 或 ~user
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~") {
+		return path
+	}
+	separators := "/"
+	if runtime.GOOS == "windows" {
+		separators = `/\`
+	}
+	name, rest := path[1:], ""
+	if i := strings.IndexAny(name, separators); i >= 0 {
+		name, rest = name[:i], name[i+1:]
+	}
+
+	var home string
+	if name == "" {
+		dir, err := os.UserHomeDir()
+		if err != nil {
+			return path
+		}
+		home = dir
+	} else {
+		u, err := user.Lookup(name)
+		if err != nil || u.HomeDir == "" {
+			return path
+		}
+		home = u.HomeDir
+	}
+	if rest == "" {
+		return home
+	}
+	return filepath.Join(home, rest)
+}
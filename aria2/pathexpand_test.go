@@ -0,0 +1,66 @@
+package aria2_test
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/dxcweb/go-aria2/aria2"
+)
+
+func TestExpandPathEnv(t *testing.T) {
+	t.Setenv("ARIA2_TEST_DIR", "/data")
+	// 先用 Setenv 登记，测试结束后恢复原值
+	t.Setenv("ARIA2_TEST_UNSET", "")
+	os.Unsetenv("ARIA2_TEST_UNSET")
+
+	tests := []struct {
+		in, want string
+	}{
+		{"$ARIA2_TEST_DIR/downloads", "/data/downloads"},
+		{"${ARIA2_TEST_DIR}/downloads", "/data/downloads"},
+		{"${ARIA2_TEST_DIR}x", "/datax"},
+		{"$ARIA2_TEST_UNSET/downloads", "$ARIA2_TEST_UNSET/downloads"},
+		{"${ARIA2_TEST_UNSET}/downloads", "${ARIA2_TEST_UNSET}/downloads"},
+		{"/price$/downloads", "/price$/downloads"},
+	}
+	if runtime.GOOS == "windows" {
+		tests = append(tests,
+			struct{ in, want string }{`%ARIA2_TEST_DIR%\downloads`, `/data\downloads`},
+			struct{ in, want string }{`%ARIA2_TEST_UNSET%\downloads`, `%ARIA2_TEST_UNSET%\downloads`},
+		)
+	} else {
+		// %VAR% 只在 Windows 上展开
+		tests = append(tests, struct{ in, want string }{"%ARIA2_TEST_DIR%/downloads", "%ARIA2_TEST_DIR%/downloads"})
+	}
+	for _, tt := range tests {
+		if got := aria2.ExpandPath(tt.in); got != tt.want {
+			t.Errorf("ExpandPath(%q) = %q，期望 %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExpandPathHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("无法获取主目录")
+	}
+	tests := []struct {
+		in, want string
+	}{
+		{"~", home},
+		{"~/downloads", filepath.Join(home, "downloads")},
+		{"/data/~/downloads", "/data/~/downloads"},
+		{"~aria2-test-no-such-user/downloads", "~aria2-test-no-such-user/downloads"},
+	}
+	if u, err := user.Current(); err == nil && u.HomeDir != "" && runtime.GOOS != "windows" {
+		tests = append(tests, struct{ in, want string }{"~" + u.Username + "/downloads", filepath.Join(u.HomeDir, "downloads")})
+	}
+	for _, tt := range tests {
+		if got := aria2.ExpandPath(tt.in); got != tt.want {
+			t.Errorf("ExpandPath(%q) = %q，期望 %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package aria2
+
+// DownloadPercentChan 添加下载任务后立即返回，通过 pct 发送整数百分比（0–100），只在百分比变化时发送
+// 下载过程中最多发送到 99，成功完成时发送且只发送一次 100；之后关闭 pct，
+// 再通过 done 发送最终结果（成功为 nil）并关闭。添加任务失败时 gid 为空，pct 直接关闭，done 返回错误。
+// pct 只保留最新的值，接收较慢时会跳过中间的百分比，但不会阻塞下载监控
+func (a *Aria2) DownloadPercentChan(url, dir, out string) (gid string, pct <-chan int, done <-chan error) {
+	percents := make(chan int, 1)
+	errs := make(chan error, 1)
+
+	fail := func(err error) (string, <-chan int, <-chan error) {
+		close(percents)
+		errs <- err
+		close(errs)
+		return "", percents, errs
+	}
+
+	last := -1
+	w, _, err := a.addWatched([]string{url}, DownloadOptions{Dir: dir, Out: out}, a.clock().Now(), func(status *DownloadStatus) {
+		p := downloadPercent(status)
+		if p != last {
+			last = p
+			sendLatest(percents, p)
+		}
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	go func() {
+		_, err := a.watchDownload(w)
+		if err == nil {
+			sendLatest(percents, 100)
+		}
+		close(percents)
+		errs <- err
+		close(errs)
+	}()
+	return w.gid, percents, errs
+}
+
+// downloadPercent 计算下载中的整数百分比，总大小未知时为 0，完成前最多为 99
+func downloadPercent(status *DownloadStatus) int {
+	total := parseLength(status.TotalLength)
+	if total <= 0 {
+		return 0
+	}
+	p := int(parseLength(status.CompletedLength) * 100 / total)
+	return min(p, 99)
+}
+
+// sendLatest 向容量为 1 的 channel 发送 v，channel 已满时用 v 替换尚未被接收的旧值
+// 只能由唯一的发送方调用
+func sendLatest(ch chan int, v int) {
+	select {
+	case ch <- v:
+	default:
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- v
+	}
+}
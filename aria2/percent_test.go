@@ -0,0 +1,75 @@
+package aria2_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dxcweb/go-aria2/aria2/aria2test"
+)
+
+func TestDownloadPercentChan(t *testing.T) {
+	clock := aria2test.NewClock(time.Unix(0, 0))
+	a, _ := attachWithClock(t, nil, clock)
+	runClock(t, clock)
+
+	gid, pct, done := a.DownloadPercentChan("http://example.com/file.bin", t.TempDir(), "file.bin")
+	if gid == "" {
+		t.Fatal(<-done)
+	}
+	var percents []int
+	for p := range pct {
+		percents = append(percents, p)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if len(percents) == 0 || percents[len(percents)-1] != 100 {
+		t.Fatalf("收到的百分比为 %v，期望以 100 结束", percents)
+	}
+	for i := 1; i < len(percents); i++ {
+		if percents[i] <= percents[i-1] {
+			t.Fatalf("百分比 %v 没有递增", percents)
+		}
+	}
+}
+
+func TestDownloadPercentChanAddFails(t *testing.T) {
+	a, _ := attach(t, nil)
+
+	gid, pct, done := a.DownloadPercentChan("not a url", t.TempDir(), "")
+	if gid != "" {
+		t.Fatalf("添加失败时 gid 为 %q，期望为空", gid)
+	}
+	if _, ok := <-pct; ok {
+		t.Fatal("添加失败时 pct 应直接关闭")
+	}
+	if err := <-done; err == nil {
+		t.Fatal("添加失败时 done 应返回错误")
+	}
+}
+
+func TestDownloadAsync(t *testing.T) {
+	clock := aria2test.NewClock(time.Unix(0, 0))
+	a, _ := attachWithClock(t, nil, clock)
+	runClock(t, clock)
+
+	dir := t.TempDir()
+	gid, done, err := a.DownloadAsync("http://example.com/file.bin", dir, "file.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case result := <-done:
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+		if result.Dir != dir || result.Status.GID != gid {
+			t.Fatalf("结果为 %+v，期望任务 %s 在 %s 中", result, gid, dir)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("下载没有结束")
+	}
+	if _, ok := <-done; ok {
+		t.Fatal("done 只应收到一个结果")
+	}
+}
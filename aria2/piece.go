@@ -0,0 +1,38 @@
+package aria2
+
+import "fmt"
+
+// validPieceSelector 检查分片选择算法，对应 --stream-piece-selector
+func validPieceSelector(mode string) error {
+	switch mode {
+	case "default", "inorder", "random", "geom":
+		return nil
+	}
+	return fmt.Errorf("无效的分片选择算法: %s", mode)
+}
+
+// WithPieceSelector 设置 HTTP/FTP 下载的分片选择算法，对应 --stream-piece-selector
+//
+//	default 尽量减少建立连接的次数（aria2 默认值）
+//	inorder 优先下载靠前的分片，配合 prioritize-piece 可以边下边播
+//	random  随机选择分片
+//	geom    开始时与 inorder 相同，之后按指数增长的距离选择靠后的分片
+//
+// inorder 会让多个连接集中在文件开头，可能降低整体下载速度
+func WithPieceSelector(mode string) Option {
+	return func(a *Aria2) error {
+		if err := validPieceSelector(mode); err != nil {
+			return err
+		}
+		a.cfg.pieceSelector = mode
+		return nil
+	}
+}
+
+// pieceSelectorArgs 返回分片选择算法的启动参数
+func (a *Aria2) pieceSelectorArgs() []string {
+	if a.cfg.pieceSelector == "" {
+		return nil
+	}
+	return []string{"--stream-piece-selector=" + a.cfg.pieceSelector}
+}
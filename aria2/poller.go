@@ -0,0 +1,129 @@
+package aria2
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// pollResult 共享轮询得到的单个任务状态
+type pollResult struct {
+	status *DownloadStatus
+	err    error
+}
+
+// statusPoller 所有被监控任务共享的状态轮询器
+// 每个周期通过一次 system.multicall 查询全部任务，再分发给各个等待者；
+// 没有任务时轮询协程退出，有新任务时重新启动
+type statusPoller struct {
+	waiters map[string][]chan pollResult
+	running bool
+}
+
+// subscribe 订阅任务的状态更新，每个轮询周期最多收到一个最新结果
+func (a *Aria2) subscribe(gid string) chan pollResult {
+	ch := make(chan pollResult, 1)
+	a.pollMu.Lock()
+	defer a.pollMu.Unlock()
+	p := &a.poller
+	if p.waiters == nil {
+		p.waiters = make(map[string][]chan pollResult)
+	}
+	p.waiters[gid] = append(p.waiters[gid], ch)
+	if !p.running {
+		p.running = true
+		go a.pollLoop()
+	}
+	return ch
+}
+
+// unsubscribe 取消订阅
+func (a *Aria2) unsubscribe(gid string, ch chan pollResult) {
+	a.pollMu.Lock()
+	defer a.pollMu.Unlock()
+	p := &a.poller
+	waiters := p.waiters[gid]
+	for i, c := range waiters {
+		if c == ch {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(p.waiters, gid)
+	} else {
+		p.waiters[gid] = waiters
+	}
+}
+
+// pollLoop 定时批量查询所有被订阅的任务，没有订阅时退出
+func (a *Aria2) pollLoop() {
+	ticker := a.clock().NewTicker(a.pollInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+		case <-a.ctx.Done():
+			a.pollMu.Lock()
+			a.poller.running = false
+			a.pollMu.Unlock()
+			return
+		}
+
+		a.pollMu.Lock()
+		if len(a.poller.waiters) == 0 {
+			a.poller.running = false
+			a.pollMu.Unlock()
+			return
+		}
+		gids := make([]string, 0, len(a.poller.waiters))
+		for gid := range a.poller.waiters {
+			gids = append(gids, gid)
+		}
+		a.pollMu.Unlock()
+
+		results := a.pollStatuses(gids)
+
+		a.pollMu.Lock()
+		for i, gid := range gids {
+			for _, ch := range a.poller.waiters[gid] {
+				// 只保留最新结果，等待者处理慢时丢弃旧结果
+				select {
+				case <-ch:
+				default:
+				}
+				ch <- results[i]
+			}
+		}
+		a.pollMu.Unlock()
+	}
+}
+
+// pollStatuses 通过一次批量调用查询多个任务的状态，结果与 gids 一一对应
+func (a *Aria2) pollStatuses(gids []string) []pollResult {
+	results := make([]pollResult, len(gids))
+	calls := make([]MethodCall, len(gids))
+	for i, gid := range gids {
+		calls[i] = MethodCall{MethodName: "aria2.tellStatus", Params: []interface{}{gid}}
+	}
+	items, err := a.Multicall(calls)
+	if err != nil {
+		for i := range results {
+			results[i].err = err
+		}
+		return results
+	}
+	for i, item := range items {
+		if item.Error != nil {
+			results[i].err = item.Error
+			continue
+		}
+		var status DownloadStatus
+		if err := json.Unmarshal(item.Result, &status); err != nil {
+			results[i].err = fmt.Errorf("解析状态失败: %w", err)
+			continue
+		}
+		results[i].status = &status
+	}
+	return results
+}
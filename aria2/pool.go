@@ -0,0 +1,106 @@
+package aria2
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// PoolStrategy 磁盘池选择目录的策略
+type PoolStrategy int
+
+const (
+	// RoundRobin 依次轮流使用各个目录
+	RoundRobin PoolStrategy = iota
+	// MostFreeSpace 使用剩余空间最多的目录
+	MostFreeSpace
+)
+
+// diskPool 多个下载目录组成的磁盘池
+type diskPool struct {
+	mu       sync.Mutex
+	dirs     []string
+	strategy PoolStrategy
+	next     int
+}
+
+// WithDiskPool 设置多个下载目录，Manager 和批量下载在未指定目录时按策略选择其中之一
+// 目录可以分布在不同磁盘上，以便大量下载分散到多个磁盘
+func WithDiskPool(dirs []string, strategy PoolStrategy) Option {
+	return func(a *Aria2) error {
+		if len(dirs) == 0 {
+			return fmt.Errorf("磁盘池至少需要一个目录")
+		}
+		if strategy != RoundRobin && strategy != MostFreeSpace {
+			return fmt.Errorf("无效的磁盘池策略: %d", strategy)
+		}
+		abs := make([]string, len(dirs))
+		for i, dir := range dirs {
+			if dir == "" {
+				return fmt.Errorf("磁盘池目录不能为空")
+			}
+			p, err := filepath.Abs(dir)
+			if err != nil {
+				return fmt.Errorf("解析磁盘池目录失败: %w", err)
+			}
+			abs[i] = p
+		}
+		a.cfg.diskPool = &diskPool{dirs: abs, strategy: strategy}
+		return nil
+	}
+}
+
+// choose 按策略选择一个目录
+func (p *diskPool) choose() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.strategy == RoundRobin {
+		dir := p.dirs[p.next%len(p.dirs)]
+		p.next++
+		return dir, nil
+	}
+
+	best := ""
+	var bestFree uint64
+	var lastErr error
+	for _, dir := range p.dirs {
+		free, err := freeSpace(existingAncestor(dir))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if best == "" || free > bestFree {
+			best, bestFree = dir, free
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("无法获取磁盘剩余空间: %w", lastErr)
+	}
+	return best, nil
+}
+
+// existingAncestor 返回 dir 自身或其最近的已存在的上级目录
+// 目录尚未创建时以所在磁盘的剩余空间为准
+func existingAncestor(dir string) string {
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// chooseDir 返回新任务使用的下载目录（绝对路径）
+// dir 为空且设置了磁盘池时从磁盘池中选择，否则与 ResolveDir 相同
+func (a *Aria2) chooseDir(dir string) (string, error) {
+	if dir == "" && a.cfg.diskPool != nil {
+		return a.cfg.diskPool.choose()
+	}
+	return a.ResolveDir(dir)
+}
@@ -0,0 +1,51 @@
+package aria2
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestConcurrentInstancesGetDistinctPorts(t *testing.T) {
+	const n = 2
+	instances := make([]*Aria2, n)
+	var wg sync.WaitGroup
+	for i := range instances {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			a, err := NewAria2()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			instances[i] = a
+		}(i)
+	}
+	wg.Wait()
+	if t.Failed() {
+		return
+	}
+	defer func() {
+		for _, a := range instances {
+			releasePort(a.port)
+		}
+	}()
+
+	if instances[0].port == instances[1].port {
+		t.Fatalf("两个实例分配到了同一个端口 %d", instances[0].port)
+	}
+}
+
+func TestFindAvailablePortOnReservesPort(t *testing.T) {
+	first := findAvailablePortOn("tcp", 6800)
+	defer releasePort(first)
+	second := findAvailablePortOn("tcp", first)
+	defer releasePort(second)
+
+	if first == second {
+		t.Fatalf("端口尚未被监听时再次分配到了 %d", first)
+	}
+	if second < first {
+		t.Fatalf("从 %d 开始查找却得到了 %d", first, second)
+	}
+}
@@ -0,0 +1,54 @@
+package aria2
+
+import "fmt"
+
+// Priority aria2c 进程的调度优先级
+type Priority int
+
+const (
+	// PriorityNormal 不调整优先级（默认）
+	PriorityNormal Priority = iota
+	// PriorityBelowNormal 低于普通优先级：Unix 上 nice 值为 10，Windows 上为 BELOW_NORMAL_PRIORITY_CLASS
+	PriorityBelowNormal
+	// PriorityIdle 最低优先级，只在系统空闲时运行：Unix 上 nice 值为 19，Windows 上为 IDLE_PRIORITY_CLASS
+	PriorityIdle
+)
+
+// String 返回优先级名称
+func (p Priority) String() string {
+	switch p {
+	case PriorityNormal:
+		return "normal"
+	case PriorityBelowNormal:
+		return "below-normal"
+	case PriorityIdle:
+		return "idle"
+	}
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+// WithProcessPriority 启动 aria2c 后降低其 CPU 调度优先级，避免后台下载影响前台程序
+// Unix 上通过 setpriority 调整 nice 值（Linux 的 I/O 优先级默认随 nice 值降低），
+// Windows 上通过 SetPriorityClass 设置优先级类别。调整失败（如权限不足）时只输出警告，不影响启动。
+// 连接已有的 aria2 时不生效
+func WithProcessPriority(p Priority) Option {
+	return func(a *Aria2) error {
+		switch p {
+		case PriorityNormal, PriorityBelowNormal, PriorityIdle:
+		default:
+			return fmt.Errorf("无效的进程优先级: %d", int(p))
+		}
+		a.cfg.priority = p
+		return nil
+	}
+}
+
+// applyPriority 调整刚启动的 aria2c 进程的优先级
+func (a *Aria2) applyPriority(pid int) {
+	if a.cfg.priority == PriorityNormal {
+		return
+	}
+	if err := setProcessPriority(pid, a.cfg.priority); err != nil {
+		a.logf("调整 aria2c 进程优先级为 %s 失败: %v", a.cfg.priority, err)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package aria2
+
+import "syscall"
+
+// niceValues 各优先级对应的 nice 值
+var niceValues = map[Priority]int{
+	PriorityNormal:      0,
+	PriorityBelowNormal: 10,
+	PriorityIdle:        19,
+}
+
+// setProcessPriority 通过 setpriority 调整进程的 nice 值
+func setProcessPriority(pid int, p Priority) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, pid, niceValues[p])
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package aria2
+
+import "syscall"
+
+var procSetPriorityClass = syscall.NewLazyDLL("kernel32.dll").NewProc("SetPriorityClass")
+
+const processSetInformation = 0x0200 // PROCESS_SET_INFORMATION
+
+// priorityClasses 各优先级对应的 Windows 优先级类别
+var priorityClasses = map[Priority]uintptr{
+	PriorityNormal:      0x00000020, // NORMAL_PRIORITY_CLASS
+	PriorityBelowNormal: 0x00004000, // BELOW_NORMAL_PRIORITY_CLASS
+	PriorityIdle:        0x00000040, // IDLE_PRIORITY_CLASS
+}
+
+// setProcessPriority 通过 SetPriorityClass 设置进程的优先级类别
+func setProcessPriority(pid int, p Priority) error {
+	handle, err := syscall.OpenProcess(processSetInformation, false, uint32(pid))
+	if err != nil {
+		return err
+	}
+	defer syscall.CloseHandle(handle)
+	r, _, err := procSetPriorityClass.Call(uintptr(handle), priorityClasses[p])
+	if r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package aria2
+
+import "os/exec"
+
+// hideWindow 非 Windows 平台无需处理
+func hideWindow(cmd *exec.Cmd) {}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package aria2
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// hideWindow 在 Windows 上隐藏控制台窗口
+func hideWindow(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.HideWindow = true
+}
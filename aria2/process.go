@@ -0,0 +1,38 @@
+package aria2
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrProcessStatsUnsupported 当前平台不支持读取进程资源占用
+var ErrProcessStatsUnsupported = errors.New("当前平台不支持读取 aria2c 进程资源占用")
+
+// ProcStats aria2c 进程的资源占用
+type ProcStats struct {
+	PID     int           // 进程 ID
+	RSS     int64         // 常驻内存（字节）
+	CPUTime time.Duration // 启动以来累计使用的 CPU 时间（用户态 + 内核态），两次采样的差值除以间隔即为 CPU 占用率
+}
+
+// PID 返回本实例启动的 aria2c 进程 ID 以及进程是否仍在运行
+// 没有启动过进程或通过 Attach 连接的服务返回 0 和 false
+func (a *Aria2) PID() (int, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cmd == nil || a.cmd.Process == nil {
+		return 0, false
+	}
+	return a.cmd.Process.Pid, a.running && a.origin == OriginLaunched
+}
+
+// ProcessStats 读取 aria2c 进程的内存和 CPU 占用（尽力而为）
+// 目前只支持 Linux（读取 /proc），其他平台返回 ErrProcessStatsUnsupported
+func (a *Aria2) ProcessStats() (*ProcStats, error) {
+	pid, running := a.PID()
+	if !running {
+		return nil, fmt.Errorf("aria2c 进程未运行")
+	}
+	return readProcStats(pid)
+}
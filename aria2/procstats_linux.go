@@ -0,0 +1,75 @@
+package aria2
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicks /proc/<pid>/stat 中 CPU 时间的单位（USER_HZ），Linux 上固定为 100
+const clockTicks = 100
+
+// readProcStats 从 /proc 读取进程的资源占用
+func readProcStats(pid int) (*ProcStats, error) {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return nil, fmt.Errorf("读取进程状态失败: %w", err)
+	}
+	// 进程名可能包含空格和括号，从最后一个右括号之后开始解析
+	end := bytes.LastIndexByte(stat, ')')
+	if end < 0 {
+		return nil, fmt.Errorf("无法解析进程状态: %s", stat)
+	}
+	// 右括号后第一个字段为 state（第 3 个字段），utime、stime 为第 14、15 个字段
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 13 {
+		return nil, fmt.Errorf("无法解析进程状态: %s", stat)
+	}
+	utime, err1 := strconv.ParseInt(fields[11], 10, 64)
+	stime, err2 := strconv.ParseInt(fields[12], 10, 64)
+	if err1 != nil || err2 != nil {
+		return nil, fmt.Errorf("无法解析进程 CPU 时间: %s", stat)
+	}
+
+	rss, err := readVmRSS(pid)
+	if err != nil {
+		return nil, err
+	}
+	return &ProcStats{
+		PID:     pid,
+		RSS:     rss,
+		CPUTime: time.Duration(utime+stime) * time.Second / clockTicks,
+	}, nil
+}
+
+// readVmRSS 从 /proc/<pid>/status 读取常驻内存大小（字节）
+func readVmRSS(pid int) (int64, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, fmt.Errorf("读取进程内存信息失败: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(scanner.Text(), "VmRSS:")
+		if !ok {
+			continue
+		}
+		// 格式为 "VmRSS:	   12345 kB"
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			break
+		}
+		kb, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			break
+		}
+		return kb * 1024, nil
+	}
+	return 0, fmt.Errorf("进程内存信息中没有 VmRSS")
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package aria2
+
+// readProcStats 非 Linux 平台暂不支持
+func readProcStats(pid int) (*ProcStats, error) {
+	return nil, ErrProcessStatsUnsupported
+}
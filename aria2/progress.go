@@ -0,0 +1,99 @@
+package aria2
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Progress 两次轮询之间的下载进度
+type Progress struct {
+	Status       *DownloadStatus   // 当前状态
+	DeltaBytes   int64             // 距上次回调新下载的字节数
+	Interval     time.Duration     // 距上次回调的时间，首次回调为 0
+	SessionBytes int64             // 本次监控期间累计下载的字节数（不含断点续传已有的部分）
+	Metadata     map[string]string // 添加任务时指定的元数据
+}
+
+// ProgressCallback 增量进度回调函数类型
+type ProgressCallback func(p *Progress)
+
+// WithProgressCallback 设置增量进度回调，每次轮询调用一次
+// 首次回调的 DeltaBytes 为当时的 completedLength；
+// 断点续传的任务以首次获得文件大小时的 completedLength 为基准，已有部分不计入增量
+func WithProgressCallback(callback ProgressCallback) Option {
+	return func(a *Aria2) error {
+		a.cfg.onProgress = callback
+		return nil
+	}
+}
+
+// progressTracker 计算两次轮询之间的增量
+type progressTracker struct {
+	resumed     bool  // 任务是否为断点续传
+	baseline    int64 // 断点续传时已有的字节数
+	baselineSet bool
+	last        int64
+	lastTick    time.Time
+	total       int64
+}
+
+// update 根据最新状态计算增量进度
+func (t *progressTracker) update(status *DownloadStatus, now time.Time) *Progress {
+	completed := parseLength(status.CompletedLength)
+	if !t.baselineSet {
+		// 断点续传的任务在获得文件大小后 completedLength 才包含已有部分
+		if t.resumed && parseLength(status.TotalLength) > 0 {
+			t.last = completed
+			t.baseline = completed
+			t.baselineSet = true
+		} else if !t.resumed {
+			t.baselineSet = true
+		}
+	}
+
+	p := &Progress{Status: status}
+	if !t.lastTick.IsZero() {
+		p.Interval = now.Sub(t.lastTick)
+	}
+	if t.baselineSet && completed > t.last {
+		p.DeltaBytes = completed - t.last
+		t.last = completed
+	}
+	t.total += p.DeltaBytes
+	p.SessionBytes = t.total
+	t.lastTick = now
+	return p
+}
+
+// sessionBytes 返回本次下载的字节数，completed 为任务结束时的已完成大小
+// 断点续传但始终没有得知已有部分大小时无法计算，返回 0
+func (t *progressTracker) sessionBytes(completed int64) int64 {
+	if !t.resumed {
+		return completed
+	}
+	if !t.baselineSet {
+		return 0
+	}
+	return completed - t.baseline
+}
+
+// hasPartialDownload 检查输出文件是否已有未完成的部分，有则 aria2 会尝试断点续传
+// 包括 aria2 自己的 .aria2 控制文件，以及其他工具留下的、没有控制文件的部分文件
+// （启动参数带有 --continue=true，服务器支持 Range 请求时 aria2 会从文件末尾继续顺序下载）
+func hasPartialDownload(dir, out string) bool {
+	if dir == "" || out == "" {
+		return false
+	}
+	path := filepath.Join(dir, out)
+	if _, err := os.Stat(path + ".aria2"); err == nil {
+		return true
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Size() > 0
+}
+
+// wasResumed 是否复用了已有的字节
+func (t *progressTracker) wasResumed() bool {
+	return t.resumed && t.baseline > 0
+}
@@ -0,0 +1,67 @@
+package aria2
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProgressTrackerBaseline(t *testing.T) {
+	tests := []struct {
+		name        string
+		resumed     bool // 添加前是否检测到已有文件
+		statuses    [][2]string
+		deltas      []int64
+		session     int64
+		wantResumed bool
+	}{
+		{
+			name:     "从头下载",
+			statuses: [][2]string{{"0", "0"}, {"1024", "0"}, {"1024", "512"}, {"1024", "1024"}},
+			deltas:   []int64{0, 0, 512, 512},
+			session:  1024,
+		},
+		{
+			name:        "添加前检测到已有文件",
+			resumed:     true,
+			statuses:    [][2]string{{"0", "0"}, {"1024", "256"}, {"1024", "1024"}},
+			deltas:      []int64{0, 0, 768},
+			session:     768,
+			wantResumed: true,
+		},
+		{
+			name:        "添加前检测到已有文件且第一次查询已有大小",
+			resumed:     true,
+			statuses:    [][2]string{{"1024", "256"}, {"1024", "512"}, {"1024", "1024"}},
+			deltas:      []int64{0, 256, 512},
+			session:     768,
+			wantResumed: true,
+		},
+		{
+			name:     "没有已有文件但第一次查询已有进度",
+			statuses: [][2]string{{"1024", "256"}, {"1024", "512"}, {"1024", "1024"}},
+			deltas:   []int64{256, 256, 512},
+			session:  1024,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := &progressTracker{resumed: tt.resumed}
+			now := time.Unix(0, 0)
+			var completed int64
+			for i, s := range tt.statuses {
+				p := tracker.update(&DownloadStatus{TotalLength: s[0], CompletedLength: s[1]}, now)
+				if p.DeltaBytes != tt.deltas[i] {
+					t.Errorf("第 %d 次的增量为 %d，期望 %d", i+1, p.DeltaBytes, tt.deltas[i])
+				}
+				completed = parseLength(s[1])
+				now = now.Add(time.Second)
+			}
+			if got := tracker.sessionBytes(completed); got != tt.session {
+				t.Errorf("本次下载字节数为 %d，期望 %d", got, tt.session)
+			}
+			if got := tracker.wasResumed(); got != tt.wantResumed {
+				t.Errorf("wasResumed 为 %v，期望 %v", got, tt.wantResumed)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package aria2
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// progressBarWidth 进度条的格子数
+const progressBarWidth = 30
+
+// spinnerFrames 总大小未知时显示的动画
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// ProgressBarCallback 返回在终端中显示进度条的回调，显示文件名、百分比、速度和剩余时间
+//
+// w 是终端时原地刷新（单个任务使用回车符，多个任务同时下载时每个任务一行），
+// 不是终端（如重定向到文件）时每次回调输出一行。总大小未知时显示动画代替进度条。
+// 同一个回调可以传给多个并发的下载，所有任务结束后换行
+func ProgressBarCallback(w io.Writer) DownloadCallback {
+	bar := &progressBar{
+		w:     w,
+		tty:   isTerminal(w),
+		lines: make(map[string]string),
+		done:  make(map[string]bool),
+	}
+	return bar.update
+}
+
+// progressBar 终端进度条的显示状态
+type progressBar struct {
+	mu    sync.Mutex
+	w     io.Writer
+	tty   bool
+	order []string          // 按首次出现的顺序排列的任务
+	lines map[string]string // 每个任务当前显示的内容
+	done  map[string]bool   // 已结束的任务
+	frame int               // 动画帧
+}
+
+// update 更新一个任务的显示
+func (b *progressBar) update(status *DownloadStatus) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	line := b.format(status)
+	if !b.tty {
+		fmt.Fprintln(b.w, line)
+		return
+	}
+
+	if _, ok := b.lines[status.GID]; !ok {
+		b.order = append(b.order, status.GID)
+	}
+	previous := len(b.lines)
+	b.lines[status.GID] = line
+	switch status.Status {
+	case "complete", "error", "removed":
+		b.done[status.GID] = true
+	}
+
+	// 光标停在最后一行的末尾，回到第一行后重新绘制所有行
+	var sb strings.Builder
+	if previous > 1 {
+		fmt.Fprintf(&sb, "\x1b[%dA", previous-1)
+	}
+	for i, gid := range b.order {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\r\x1b[2K")
+		sb.WriteString(b.lines[gid])
+	}
+	// 所有任务结束后换行，之后的任务从新的一行开始
+	if len(b.done) == len(b.order) {
+		sb.WriteString("\n")
+		b.order = nil
+		b.lines = make(map[string]string)
+		b.done = make(map[string]bool)
+	}
+	io.WriteString(b.w, sb.String())
+}
+
+// format 生成一个任务的显示内容
+func (b *progressBar) format(status *DownloadStatus) string {
+	name := filepath.Base(status.path())
+	if status.path() == "" {
+		name = status.GID
+	}
+	total := parseLength(status.TotalLength)
+	completed := parseLength(status.CompletedLength)
+	speed := parseLength(status.DownloadSpeed)
+
+	if verified, total, ok := status.VerifyProgress(); ok {
+		if total <= 0 {
+			return fmt.Sprintf("%s  校验中…", name)
+		}
+		return fmt.Sprintf("%s  校验中… %.0f%%", name, float64(verified)*100/float64(total))
+	}
+
+	switch status.Status {
+	case "complete":
+		return fmt.Sprintf("%s  完成  %s", name, FormatBytes(completed))
+	case "error":
+		return fmt.Sprintf("%s  失败: %s", name, status.ErrorMessage)
+	case "removed":
+		return fmt.Sprintf("%s  已取消", name)
+	case "paused":
+		return fmt.Sprintf("%s  已暂停  %s", name, FormatBytes(completed))
+	}
+
+	if total <= 0 {
+		b.frame = (b.frame + 1) % len(spinnerFrames)
+		return fmt.Sprintf("%s  [%s]  %s  %s", name, spinnerFrames[b.frame], FormatBytes(completed), FormatSpeed(speed))
+	}
+
+	filled := int(completed * progressBarWidth / total)
+	if filled > progressBarWidth {
+		filled = progressBarWidth
+	}
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+	if filled > 0 && filled < progressBarWidth {
+		bar = strings.Repeat("=", filled-1) + ">" + strings.Repeat(" ", progressBarWidth-filled)
+	}
+	eta := "--:--"
+	if speed > 0 {
+		eta = formatETA(time.Duration((total-completed)/speed) * time.Second)
+	}
+	return fmt.Sprintf("%s  [%s] %5.1f%%  %s/%s  %s  ETA %s",
+		name, bar, float64(completed)*100/float64(total),
+		FormatBytes(completed), FormatBytes(total), FormatSpeed(speed), eta)
+}
+
+// formatETA 将剩余时间格式化为 mm:ss 或 h:mm:ss
+func formatETA(d time.Duration) string {
+	s := int(d.Seconds())
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s%3600/60, s%60)
+	}
+	return fmt.Sprintf("%02d:%02d", s/60, s%60)
+}
+
+// isTerminal 判断 w 是否为终端
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
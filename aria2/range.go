@@ -0,0 +1,176 @@
+package aria2
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// errCodeRangeRejected aria2 错误代码 8：需要断点续传但服务器不支持，
+// 服务器返回的 Content-Range 与 aria2 自己请求的范围不一致时也使用这个代码
+const errCodeRangeRejected = "8"
+
+// DownloadRange 只下载文件中 [start, end] 范围（包含两端）的字节，阻塞直到完成，返回文件路径
+// 通过 Range 请求头请求部分内容，是否只传输这部分数据取决于服务器：
+//   - 服务器返回 206 时只下载该范围，超出文件末尾的部分会被服务器截掉
+//   - 服务器忽略 Range 返回 200，或 aria2 不接受服务器返回的范围时，从头按顺序下载，
+//     收到 end+1 个字节后停止，再从文件中截取所需范围
+//
+// 为了让数据按顺序到达，该任务只使用单个连接下载。
+// aria2 只能通过 Content-Length 判断服务器是否返回了部分内容，start 大于 0 且文件本身小于请求范围时无法区分
+func (a *Aria2) DownloadRange(url string, start, end int64, dir, out string) (string, error) {
+	if start < 0 || end < start {
+		return "", fmt.Errorf("无效的下载范围: %d-%d", start, end)
+	}
+	if err := validateURI(url); err != nil {
+		return "", err
+	}
+	dir, err := a.ResolveDir(dir)
+	if err != nil {
+		return "", err
+	}
+	options := map[string]interface{}{
+		"dir":                       dir,
+		"split":                     "1",
+		"max-connection-per-server": "1",
+		"stream-piece-selector":     "inorder",
+	}
+	if out != "" {
+		options["out"] = out
+	}
+
+	ranged := copyOptions(options)
+	ranged["header"] = []string{fmt.Sprintf("Range: bytes=%d-%d", start, end)}
+	path, err := a.downloadRange(url, ranged, start, end)
+	var dlErr *DownloadError
+	if err == nil || !errors.As(err, &dlErr) || dlErr.Code != errCodeRangeRejected {
+		return path, err
+	}
+	// aria2 检查响应的 Content-Range 时拒绝了与自己的请求不一致的范围，清理后改为从头下载再截取
+	a.RemoveDownloadResult(dlErr.GID)
+	if dlErr.Status != nil && dlErr.Status.path() != "" {
+		os.Remove(dlErr.Status.path())
+		os.Remove(dlErr.Status.path() + ".aria2")
+	}
+	a.logf("aria2 不接受服务器返回的范围，改为从头下载前 %d 个字节", end+1)
+	return a.downloadRange(url, options, start, end)
+}
+
+// downloadRange 添加任务并监控，服务器返回完整文件时收到足够的数据后停止并截取
+func (a *Aria2) downloadRange(url string, options map[string]interface{}, start, end int64) (string, error) {
+	gid, err := a.addUri([]string{url}, options)
+	if err != nil {
+		return "", err
+	}
+	want := end - start + 1
+	ranged := options["header"] != nil
+
+	ticker := a.clock().NewTicker(a.pollInterval())
+	defer ticker.Stop()
+	for {
+		status, err := a.tellStatusRetry(gid)
+		if err != nil {
+			return "", err
+		}
+		total := parseLength(status.TotalLength)
+		// 总大小等于请求的长度说明服务器返回了 206，否则是完整文件，需要截取
+		partial := ranged && total > 0 && total <= want
+
+		switch status.Status {
+		case "complete":
+			if partial || total == 0 {
+				return status.path(), nil
+			}
+			return status.path(), cutRange(status.path(), start, want)
+		case "error":
+			return "", newDownloadError(status)
+		case "removed":
+			return "", fmt.Errorf("下载已取消")
+		}
+
+		if total > 0 && !partial && contiguousLength(status) >= end+1 {
+			path := status.path()
+			if err := a.stopRange(gid); err != nil {
+				return "", err
+			}
+			os.Remove(path + ".aria2")
+			return path, cutRange(path, start, want)
+		}
+
+		select {
+		case <-ticker.C():
+		case <-a.ctx.Done():
+			a.ForceRemove(gid)
+			return "", fmt.Errorf("ctx上下文已取消")
+		}
+	}
+}
+
+// stopRange 已收到足够的数据，删除任务并等待 aria2 关闭文件
+func (a *Aria2) stopRange(gid string) error {
+	if err := a.ForceRemove(gid); err != nil {
+		return fmt.Errorf("停止下载失败: %w", err)
+	}
+	deadline := a.clock().Now().Add(10 * time.Second)
+	for a.clock().Now().Before(deadline) {
+		status, err := a.TellStatusKeys(gid, []string{"status"})
+		if err != nil || status.Status == "removed" {
+			break
+		}
+		<-a.clock().After(100 * time.Millisecond)
+	}
+	a.RemoveDownloadResult(gid)
+	return nil
+}
+
+// cutRange 将文件替换为其中从 start 开始的 n 个字节，文件较短时保留到末尾
+func cutRange(path string, start, n int64) error {
+	if start == 0 {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("截取下载范围失败: %w", err)
+		}
+		if info.Size() <= n {
+			return nil
+		}
+		if err := os.Truncate(path, n); err != nil {
+			return fmt.Errorf("截取下载范围失败: %w", err)
+		}
+		return nil
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("截取下载范围失败: %w", err)
+	}
+	defer src.Close()
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".range-*.tmp")
+	if err != nil {
+		return fmt.Errorf("截取下载范围失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, io.NewSectionReader(src, start, n)); err != nil {
+		tmp.Close()
+		return fmt.Errorf("截取下载范围失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("截取下载范围失败: %w", err)
+	}
+	src.Close()
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("截取下载范围失败: %w", err)
+	}
+	return nil
+}
+
+// copyOptions 复制任务选项
+func copyOptions(options map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(options))
+	for k, v := range options {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,169 @@
+package aria2_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dxcweb/go-aria2/aria2"
+	"github.com/dxcweb/go-aria2/aria2/aria2test"
+)
+
+// rangeScript 返回按是否带有 Range 请求头生成状态的脚本，以及记录每次添加任务时请求头的函数
+func rangeScript(ranged, full func(path string) []aria2.DownloadStatus) (aria2test.Script, func() [][]string) {
+	var mu sync.Mutex
+	var headers [][]string
+	script := func(uris []string, options map[string]interface{}) []aria2.DownloadStatus {
+		dir, _ := options["dir"].(string)
+		out, _ := options["out"].(string)
+		var header []string
+		if values, ok := options["header"].([]interface{}); ok {
+			for _, v := range values {
+				s, _ := v.(string)
+				header = append(header, s)
+			}
+		}
+		mu.Lock()
+		headers = append(headers, header)
+		mu.Unlock()
+
+		path := filepath.Join(dir, out)
+		if header != nil {
+			return ranged(path)
+		}
+		return full(path)
+	}
+	return script, func() [][]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([][]string(nil), headers...)
+	}
+}
+
+// rangeStatus 生成文件为 path 的任务状态
+func rangeStatus(path, status, total, completed string) aria2.DownloadStatus {
+	return aria2.DownloadStatus{
+		Status:          status,
+		TotalLength:     total,
+		CompletedLength: completed,
+		Files:           []aria2.File{{Path: path, Selected: "true"}},
+	}
+}
+
+// downloadRange 连接测试服务器并下载 [start, end] 范围
+func downloadRange(t *testing.T, script aria2test.Script, dir string, start, end int64) (string, *aria2test.Server, error) {
+	t.Helper()
+	clock := aria2test.NewClock(time.Unix(0, 0))
+	a, srv := attachWithClock(t, script, clock)
+	runClock(t, clock)
+	path, err := a.DownloadRange("http://example.com/file.bin", start, end, dir, "file.bin")
+	return path, srv, err
+}
+
+func TestDownloadRangePartialContent(t *testing.T) {
+	// 服务器返回 206：任务的大小就是请求的长度，不需要截取
+	script, headers := rangeScript(func(path string) []aria2.DownloadStatus {
+		return []aria2.DownloadStatus{
+			rangeStatus(path, "active", "100", "50"),
+			rangeStatus(path, "complete", "100", "100"),
+		}
+	}, nil)
+
+	dir := t.TempDir()
+	path, srv, err := downloadRange(t, script, dir, 100, 199)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(dir, "file.bin") {
+		t.Fatalf("文件路径为 %q", path)
+	}
+	if got := headers(); len(got) != 1 || len(got[0]) != 1 || got[0][0] != "Range: bytes=100-199" {
+		t.Fatalf("添加任务时的请求头为 %q，期望只添加一次带 Range 的任务", got)
+	}
+	if n := count(srv.Calls(), "aria2.forceRemove"); n != 0 {
+		t.Fatalf("服务器返回 206 时不应停止任务，forceRemove 调用了 %d 次", n)
+	}
+}
+
+func TestDownloadRangeFullContent(t *testing.T) {
+	// 服务器忽略 Range 返回 200：收到前 end+1 个字节后停止下载并截取
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "file.bin"), []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	script, _ := rangeScript(func(path string) []aria2.DownloadStatus {
+		status := rangeStatus(path, "active", "1000", "8")
+		status.PieceLength = "4"
+		status.NumPieces = "250"
+		status.Bitfield = "c0" + strings.Repeat("00", 31) // 前两块（8 个字节）已完成
+		return []aria2.DownloadStatus{status}
+	}, nil)
+
+	path, srv, err := downloadRange(t, script, dir, 2, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := count(srv.Calls(), "aria2.forceRemove"); n != 1 {
+		t.Fatalf("收到足够的数据后应停止任务，forceRemove 调用了 %d 次", n)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "2345" {
+		t.Fatalf("文件内容为 %q，期望截取后的 \"2345\"", data)
+	}
+}
+
+func TestDownloadRangeFallsBackWhenRangeRejected(t *testing.T) {
+	dir := t.TempDir()
+	script, headers := rangeScript(func(path string) []aria2.DownloadStatus {
+		status := rangeStatus(path, "error", "0", "0")
+		status.ErrorCode = "8"
+		status.ErrorMessage = "Invalid range header. Request: 0-0/0, Response: 0-3/10"
+		return []aria2.DownloadStatus{status}
+	}, func(path string) []aria2.DownloadStatus {
+		os.WriteFile(path, []byte("0123456789"), 0644)
+		return []aria2.DownloadStatus{rangeStatus(path, "complete", "10", "10")}
+	})
+
+	path, srv, err := downloadRange(t, script, dir, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := headers(); len(got) != 2 || got[1] != nil {
+		t.Fatalf("添加任务时的请求头为 %q，期望第二次不带 Range", got)
+	}
+	if n := count(srv.Calls(), "aria2.removeDownloadResult"); n != 1 {
+		t.Fatalf("改为从头下载前应清理失败的任务，removeDownloadResult 调用了 %d 次", n)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "0123" {
+		t.Fatalf("文件内容为 %q，期望截取后的 \"0123\"", data)
+	}
+}
+
+func TestDownloadRangeOtherErrorsDoNotFallBack(t *testing.T) {
+	script, headers := rangeScript(func(path string) []aria2.DownloadStatus {
+		status := rangeStatus(path, "error", "0", "0")
+		status.ErrorCode = "3"
+		status.ErrorMessage = "Resource not found for range request"
+		return []aria2.DownloadStatus{status}
+	}, nil)
+
+	_, _, err := downloadRange(t, script, t.TempDir(), 0, 3)
+	var dlErr *aria2.DownloadError
+	if !errors.As(err, &dlErr) || dlErr.Code != "3" {
+		t.Fatalf("错误为 %v，期望原样返回下载错误", err)
+	}
+	if got := headers(); len(got) != 1 {
+		t.Fatalf("添加了 %d 次任务，错误代码不是 8 时不应改为从头下载", len(got))
+	}
+}
@@ -0,0 +1,80 @@
+package aria2
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrVerifyFailed 校验完成但已有文件不完整或与种子不一致
+var ErrVerifyFailed = errors.New("文件校验未通过")
+
+// WithCheckIntegrity 添加任务时校验已有文件的分片哈希，只重新下载损坏或缺失的部分，对应 --check-integrity
+// 只对 BT、Metalink 以及指定了校验和的任务有效。与 WithHashCheckOnResume 设置同一个选项，以最后设置的为准
+func WithCheckIntegrity(enabled bool) Option {
+	return func(a *Aria2) error {
+		a.cfg.checkIntegrity = enabled
+		a.cfg.seedUnverified = false
+		return nil
+	}
+}
+
+// Recheck 使用种子校验 dir 中已下载的文件，不重新下载，返回任务的 GID
+func (a *Aria2) Recheck(torrentData []byte, dir string) (string, error) {
+	return a.RecheckWithCallback(torrentData, dir, nil)
+}
+
+// RecheckWithCallback 使用种子校验 dir 中已下载的文件，校验期间每次查询都会调用 callback，
+// 可通过状态的 VerifyProgress 显示校验进度。
+// 全部分片校验通过时返回 nil；有分片损坏或缺失时删除任务（不会继续下载）并返回 ErrVerifyFailed
+func (a *Aria2) RecheckWithCallback(torrentData []byte, dir string, callback DownloadCallback) (string, error) {
+	dir, err := a.ResolveDir(dir)
+	if err != nil {
+		return "", err
+	}
+	gid, err := a.addTorrent(torrentData, map[string]interface{}{
+		"dir":                dir,
+		"check-integrity":    "true",
+		"bt-hash-check-seed": "true",
+		"seed-time":          "0", // 校验通过后立即结束，不做种
+	})
+	if err != nil {
+		return "", err
+	}
+
+	ticker := a.clock().NewTicker(a.pollInterval())
+	defer ticker.Stop()
+	for {
+		status, err := a.tellStatusRetry(gid)
+		if err != nil {
+			return gid, err
+		}
+		if callback != nil {
+			callback(status)
+		}
+
+		verifying := status.Verifying()
+		switch {
+		case status.Status == "complete":
+			return gid, nil
+		case status.Status == "error":
+			return gid, newDownloadError(status)
+		case status.Status == "removed":
+			return gid, fmt.Errorf("校验已取消")
+		case status.Status == "active" && !verifying:
+			// 校验结束：已完成大小即为校验通过的部分
+			completed, total := parseLength(status.CompletedLength), parseLength(status.TotalLength)
+			if total > 0 && completed == total {
+				// 做种时间为 0，任务很快会变为 complete
+				break
+			}
+			a.ForceRemove(gid)
+			return gid, fmt.Errorf("%w: 已校验 %d/%d 字节", ErrVerifyFailed, completed, total)
+		}
+
+		select {
+		case <-ticker.C():
+		case <-a.ctx.Done():
+			return gid, fmt.Errorf("ctx上下文已取消")
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package aria2
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxRemoteBinarySize 下载的 aria2c 二进制文件的最大大小
+const maxRemoteBinarySize = 64 << 20
+
+// remoteBinaryTimeout 启动时自动下载二进制文件的超时时间
+const remoteBinaryTimeout = 5 * time.Minute
+
+// WithRemoteBinary 从 url 下载当前平台的 aria2c 二进制文件代替内嵌版本，sha256 为文件的 SHA-256（十六进制）
+// 启动时如果数据目录中没有校验通过的文件会自动下载；下载或校验失败时回退到内嵌的二进制文件
+func WithRemoteBinary(url, sha256 string) Option {
+	return func(a *Aria2) error {
+		if err := validateURI(url); err != nil {
+			return err
+		}
+		sum, err := hex.DecodeString(sha256)
+		if err != nil || len(sum) != 32 {
+			return fmt.Errorf("无效的 SHA-256: %q", sha256)
+		}
+		a.cfg.remoteBinaryURL = url
+		a.cfg.remoteBinarySHA256 = strings.ToLower(sha256)
+		return nil
+	}
+}
+
+// DownloadBinary 下载 WithRemoteBinary 指定的二进制文件，校验 SHA-256 后安装到数据目录
+// 已安装且校验通过时不会重复下载
+func (a *Aria2) DownloadBinary(ctx context.Context) error {
+	if a.cfg.remoteBinaryURL == "" {
+		return fmt.Errorf("未设置 WithRemoteBinary")
+	}
+	binaryPath, err := a.remoteBinaryPath()
+	if err != nil {
+		return err
+	}
+	if isBinaryUpToDate(binaryPath, a.cfg.remoteBinarySHA256) {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", a.cfg.remoteBinaryURL, nil)
+	if err != nil {
+		return fmt.Errorf("创建下载请求失败: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("下载 aria2c 失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("下载 aria2c 失败: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteBinarySize+1))
+	if err != nil {
+		return fmt.Errorf("下载 aria2c 失败: %w", err)
+	}
+	if len(data) > maxRemoteBinarySize {
+		return fmt.Errorf("aria2c 文件过大，超过 %d 字节", maxRemoteBinarySize)
+	}
+	sum := sha256.Sum256(data)
+	if hash := hex.EncodeToString(sum[:]); hash != a.cfg.remoteBinarySHA256 {
+		return fmt.Errorf("aria2c 校验失败: 期望 %s，实际 %s", a.cfg.remoteBinarySHA256, hash)
+	}
+
+	extractMu.Lock()
+	defer extractMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(binaryPath), 0755); err != nil {
+		return fmt.Errorf("创建应用程序目录失败: %w", err)
+	}
+	if err := writeFileAtomic(binaryPath, data, 0755); err != nil {
+		return fmt.Errorf("写入二进制文件失败: %w", err)
+	}
+	if err := writeFileAtomic(binaryPath+".version", []byte(a.cfg.remoteBinarySHA256), 0644); err != nil {
+		return fmt.Errorf("写入版本文件失败: %w", err)
+	}
+	return nil
+}
+
+// remoteBinaryPath 返回下载的二进制文件的安装路径，与内嵌版本分开存放，互不覆盖
+func (a *Aria2) remoteBinaryPath() (string, error) {
+	filename, err := GetEmbeddedBinaryName()
+	if err != nil {
+		return "", err
+	}
+	appDir := a.cfg.dataDir
+	if appDir == "" {
+		appDir, err = getAppDataDir()
+		if err != nil {
+			return "", fmt.Errorf("无法获取应用程序数据目录: %w", err)
+		}
+	}
+	return filepath.Join(appDir, "remote", filename), nil
+}
+
+// remoteBinary 返回可用的下载版二进制文件路径，需要时先下载
+func (a *Aria2) remoteBinary() (string, error) {
+	ctx, cancel := context.WithTimeout(a.ctx, remoteBinaryTimeout)
+	defer cancel()
+	if err := a.DownloadBinary(ctx); err != nil {
+		return "", err
+	}
+	return a.remoteBinaryPath()
+}
+
+// resolveRemoteBinary 设置了 WithRemoteBinary 时返回下载版二进制文件的路径，需要时先下载；
+// 未设置或不可用时返回空字符串，启动时使用内嵌版本
+func (a *Aria2) resolveRemoteBinary() string {
+	if a.cfg.remoteBinaryURL == "" {
+		return ""
+	}
+	binaryPath, err := a.remoteBinary()
+	if err != nil {
+		a.logf("无法使用下载的 aria2c，改用内嵌版本: %v", err)
+		return ""
+	}
+	return binaryPath
+}
+
+// ensureBinary 启动前确认二进制文件仍然存在
+func (a *Aria2) ensureBinary(binaryPath string) error {
+	if a.cfg.remoteBinaryURL != "" {
+		remotePath, err := a.remoteBinaryPath()
+		if err == nil && binaryPath == remotePath {
+			if _, err := os.Stat(binaryPath); err != nil {
+				return fmt.Errorf("%w: %s", ErrBinaryQuarantined, binaryPath)
+			}
+			return nil
+		}
+	}
+	return ensureExtractedBinary(binaryPath)
+}
@@ -0,0 +1,72 @@
+package aria2
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSpawnDownloadsBinaryWithoutHoldingLock(t *testing.T) {
+	data := []byte("fake aria2c")
+	sum := sha256.Sum256(data)
+	requested := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(requested)
+		<-release
+		w.Write(data)
+	}))
+	defer srv.Close()
+	defer func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	}()
+
+	// netrc 文件在启动前被删除，下载完成后 Spawn 会在启动进程前返回错误
+	netrc := filepath.Join(t.TempDir(), "netrc")
+	if err := os.WriteFile(netrc, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	a := newInstance(t,
+		WithDataDir(t.TempDir()),
+		WithRemoteBinary(srv.URL+"/aria2c", hex.EncodeToString(sum[:])),
+		WithNetrc(netrc),
+	)
+	os.Remove(netrc)
+
+	spawned := make(chan error, 1)
+	go func() { spawned <- a.Spawn() }()
+	select {
+	case <-requested:
+	case err := <-spawned:
+		t.Fatalf("没有下载二进制文件，Spawn 返回了 %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("没有下载二进制文件")
+	}
+
+	// 下载期间 Stop 不应被阻塞
+	stopped := make(chan error, 1)
+	go func() { stopped <- a.Stop() }()
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("下载二进制文件期间 Stop 被阻塞")
+	}
+
+	close(release)
+	if err := <-spawned; err == nil {
+		a.Stop()
+		t.Fatal("netrc 文件已删除时 Spawn 应返回错误")
+	}
+}
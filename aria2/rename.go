@@ -0,0 +1,71 @@
+package aria2
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrOutNotChangeable aria2 拒绝修改任务的输出文件名
+// 通常是任务已在下载中或类型不支持，可以在下载完成后使用 RenameCompleted 重命名文件
+var ErrOutNotChangeable = errors.New("aria2 不允许修改该任务的输出文件名")
+
+// Rename 修改下载任务的输出文件名
+// 通过 aria2.changeOption 修改 out 选项，对尚未开始的任务一般都能成功；
+// 正在下载的任务可能被 aria2 拒绝（返回 ErrOutNotChangeable）或导致任务重新开始。
+// 任务不存在等其他错误原样返回
+func (a *Aria2) Rename(gid, newOut string) error {
+	if err := validateOut(newOut); err != nil {
+		return err
+	}
+	if err := a.ChangeOption(gid, map[string]string{"out": newOut}); err != nil {
+		// 只有任务存在且正在下载时才是 aria2 拒绝修改
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) {
+			status, statusErr := a.TellStatusKeys(gid, []string{"status"})
+			if statusErr == nil && status.Status == "active" {
+				return fmt.Errorf("%w: %w", ErrOutNotChangeable, err)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// RenameCompleted 下载完成后直接重命名磁盘上的文件，返回新的文件路径
+// 作为 Rename 被拒绝时的备选方案
+func (a *Aria2) RenameCompleted(gid, newOut string) (string, error) {
+	if err := validateOut(newOut); err != nil {
+		return "", err
+	}
+	status, err := a.TellStatus(gid)
+	if err != nil {
+		return "", err
+	}
+	if status.Status != "complete" {
+		return "", fmt.Errorf("任务尚未完成: %s", status.Status)
+	}
+	oldPath := status.path()
+	if oldPath == "" {
+		return "", fmt.Errorf("无法获取任务的文件路径: %s", gid)
+	}
+
+	newPath := filepath.Join(filepath.Dir(oldPath), newOut)
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return "", fmt.Errorf("重命名文件失败: %w", err)
+	}
+	return newPath, nil
+}
+
+// validateOut 检查输出文件名，不允许包含路径
+func validateOut(out string) error {
+	if out == "" {
+		return fmt.Errorf("文件名不能为空")
+	}
+	if strings.ContainsAny(out, `/\`) || out == "." || out == ".." {
+		return fmt.Errorf("文件名不能包含路径: %s", out)
+	}
+	return nil
+}
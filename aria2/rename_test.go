@@ -0,0 +1,60 @@
+package aria2_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dxcweb/go-aria2/aria2"
+)
+
+func TestRenameErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       error // tellStatus 返回的错误，nil 表示任务正在下载
+		wantRejected bool
+	}{
+		{name: "任务正在下载", wantRejected: true},
+		{name: "任务不存在", status: &aria2.RPCError{Code: 1, Message: "GID 2089b05ecca3d829 is not found"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := newRPCServer(t, func(req rpcRequest) (interface{}, error) {
+				switch req.Method {
+				case "aria2.changeOption":
+					return nil, &aria2.RPCError{Code: 1, Message: "changeOption failed"}
+				case "aria2.tellStatus":
+					if tt.status != nil {
+						return nil, tt.status
+					}
+					return map[string]string{"gid": "2089b05ecca3d829", "status": "active"}, nil
+				}
+				return "OK", nil
+			})
+			a, err := aria2.NewAria2(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = a.Rename("2089b05ecca3d829", "new.bin")
+			if got := errors.Is(err, aria2.ErrOutNotChangeable); got != tt.wantRejected {
+				t.Fatalf("errors.Is(err, ErrOutNotChangeable) = %v，期望 %v: %v", got, tt.wantRejected, err)
+			}
+			var rpcErr *aria2.RPCError
+			if !errors.As(err, &rpcErr) || rpcErr.Message != "changeOption failed" {
+				t.Fatalf("错误为 %v，期望包含 aria2 返回的原始错误", err)
+			}
+		})
+	}
+}
+
+func TestRenameRejectsPath(t *testing.T) {
+	a, err := aria2.NewAria2()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, out := range []string{"", "..", "dir/file.bin", `dir\file.bin`} {
+		if err := a.Rename("2089b05ecca3d829", out); err == nil {
+			t.Errorf("Rename(%q) 应返回错误", out)
+		}
+	}
+}
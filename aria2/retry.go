@@ -0,0 +1,62 @@
+package aria2
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// aria2 内部重试（--max-tries/--retry-wait）发生在单个任务内部：连接失败、服务器返回 503 等
+// 临时错误时由 aria2 自己重新连接，任务在重试次数用完之前不会变为 error 状态。
+// 本包在轮询任务状态时对 RPC 调用的重试（tellStatusRetry）只处理与 aria2c 之间的网络错误，
+// 与这里的下载重试互不影响；设置了较多的重试次数和较长的等待时间时，任务会更晚报告错误
+
+// WithMaxTries 设置所有任务的默认最大尝试次数，对应 --max-tries，0 表示不限制（aria2 默认为 5）
+func WithMaxTries(n int) Option {
+	return func(a *Aria2) error {
+		if n < 0 {
+			return fmt.Errorf("最大尝试次数不能为负数: %d", n)
+		}
+		a.cfg.maxTries = n
+		return nil
+	}
+}
+
+// WithRetryWait 设置所有任务的默认重试间隔，对应 --retry-wait，按秒向上取整（aria2 默认为 0）
+func WithRetryWait(d time.Duration) Option {
+	return func(a *Aria2) error {
+		if d < 0 {
+			return fmt.Errorf("重试间隔不能为负数: %v", d)
+		}
+		a.cfg.retryWait = d
+		return nil
+	}
+}
+
+// retrySeconds 将重试间隔转换为 aria2 使用的秒数，不足一秒的部分向上取整
+func retrySeconds(d time.Duration) string {
+	return strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10)
+}
+
+// retryArgs 返回重试相关的启动参数
+func (a *Aria2) retryArgs() []string {
+	var args []string
+	if a.cfg.maxTries >= 0 {
+		args = append(args, "--max-tries="+strconv.Itoa(a.cfg.maxTries))
+	}
+	if a.cfg.retryWait > 0 {
+		args = append(args, "--retry-wait="+retrySeconds(a.cfg.retryWait))
+	}
+	return args
+}
+
+// validateRetry 检查任务的重试选项
+func (o *DownloadOptions) validateRetry() error {
+	if o.MaxTries < 0 {
+		return fmt.Errorf("最大尝试次数不能为负数: %d", o.MaxTries)
+	}
+	if o.RetryWait < 0 {
+		return fmt.Errorf("重试间隔不能为负数: %v", o.RetryWait)
+	}
+	return nil
+}
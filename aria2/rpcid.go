@@ -0,0 +1,40 @@
+package aria2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// ErrResponseIDMismatch JSON-RPC 响应的 id 与请求不一致
+var ErrResponseIDMismatch = errors.New("JSON-RPC 响应 ID 不匹配")
+
+// correlationKey 关联 ID 在 context 中的键
+type correlationKey struct{}
+
+// WithCorrelationID 返回携带关联 ID 的 context，通过 CallContext 发送的请求会把它嵌入 JSON-RPC 的 id 中，
+// 便于把 aria2 日志和错误信息与应用层的请求对应起来
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID 返回 context 中的关联 ID，没有时返回空字符串
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// CallContext 与 Call 相同，ctx 用于控制请求的超时和取消，并可以通过 WithCorrelationID 携带关联 ID
+func (a *Aria2) CallContext(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+	return a.call(ctx, method, params)
+}
+
+// nextRequestID 生成请求 ID：实例内单调递增的序号，有关联 ID 时格式为 "关联ID/序号"
+func (a *Aria2) nextRequestID(ctx context.Context) string {
+	id := strconv.FormatUint(a.requestID.Add(1), 10)
+	if correlation := CorrelationID(ctx); correlation != "" {
+		return correlation + "/" + id
+	}
+	return id
+}
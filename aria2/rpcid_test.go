@@ -0,0 +1,61 @@
+package aria2_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dxcweb/go-aria2/aria2"
+)
+
+func TestCallRejectsMismatchedResponseID(t *testing.T) {
+	opts := newRawRPCServer(t, func(req rpcRequest) map[string]interface{} {
+		return map[string]interface{}{"jsonrpc": "2.0", "id": req.ID + "0", "result": "OK"}
+	})
+	a, err := aria2.NewAria2(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := a.Call("aria2.getVersion", nil); !errors.Is(err, aria2.ErrResponseIDMismatch) {
+		t.Fatalf("错误为 %v，期望 ErrResponseIDMismatch", err)
+	}
+}
+
+func TestCallAcceptsNullIDOnError(t *testing.T) {
+	// aria2 无法解析请求时返回 id 为 null 的错误响应
+	opts := newRawRPCServer(t, func(req rpcRequest) map[string]interface{} {
+		return map[string]interface{}{"jsonrpc": "2.0", "id": nil, "error": map[string]interface{}{"code": -32700, "message": "Parse error."}}
+	})
+	a, err := aria2.NewAria2(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = a.Call("aria2.getVersion", nil)
+	var rpcErr *aria2.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32700 {
+		t.Fatalf("错误为 %v，期望 aria2 返回的解析错误", err)
+	}
+}
+
+func TestCallContextSendsCorrelationID(t *testing.T) {
+	var id string
+	opts := newRPCServer(t, func(req rpcRequest) (interface{}, error) {
+		id = req.ID
+		return "OK", nil
+	})
+	a, err := aria2.NewAria2(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := aria2.WithCorrelationID(context.Background(), "req-42")
+	if _, err := a.CallContext(ctx, "aria2.getVersion", nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(id, "req-42/") {
+		t.Fatalf("请求 ID 为 %q，期望以关联 ID 开头", id)
+	}
+}
@@ -0,0 +1,51 @@
+package aria2
+
+import (
+	"context"
+	"fmt"
+)
+
+// defaultMaxConcurrentRPC 默认同时进行的 RPC 请求数上限
+const defaultMaxConcurrentRPC = 16
+
+// WithMaxConcurrentRPC 设置同时进行的 RPC 请求数上限，默认 16
+// aria2 单线程处理 RPC 请求，大量协程同时轮询时请求会堆积并超时；超过上限的请求排队等待，
+// 可通过 InFlightRPC 查看当前正在进行的请求数
+func WithMaxConcurrentRPC(n int) Option {
+	return func(a *Aria2) error {
+		if n < 1 {
+			return fmt.Errorf("RPC 并发数必须大于0: %d", n)
+		}
+		a.cfg.maxConcurrentRPC = n
+		return nil
+	}
+}
+
+// InFlightRPC 返回当前正在进行（已发出、尚未收到响应）的 RPC 请求数，用于诊断
+func (a *Aria2) InFlightRPC() int {
+	return int(a.inFlight.Load())
+}
+
+// acquireRPC 占用一个 RPC 请求位置，已满时等待，直到 ctx 结束
+func (a *Aria2) acquireRPC(ctx context.Context) error {
+	a.rpcOnce.Do(func() {
+		n := a.cfg.maxConcurrentRPC
+		if n == 0 {
+			n = defaultMaxConcurrentRPC
+		}
+		a.rpcSlots = make(chan struct{}, n)
+	})
+	select {
+	case a.rpcSlots <- struct{}{}:
+		a.inFlight.Add(1)
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("等待 RPC 请求位置时取消: %w", ctx.Err())
+	}
+}
+
+// releaseRPC 释放 acquireRPC 占用的位置
+func (a *Aria2) releaseRPC() {
+	a.inFlight.Add(-1)
+	<-a.rpcSlots
+}
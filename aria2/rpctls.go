@@ -0,0 +1,92 @@
+package aria2
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// WithRPCTLS 通过 HTTPS/WSS 访问 RPC 服务
+// certFile 和 keyFile 为启动 aria2c 时使用的证书和私钥，对应 --rpc-certificate 和 --rpc-private-key，
+// 只连接已有服务（Attach）时可以为空；caFile 为校验服务端证书的 CA，为空时使用系统根证书。
+// 客户端通过 127.0.0.1（IPv6Only 时为 ::1）访问 RPC，证书需要包含对应的 IP 地址
+func WithRPCTLS(certFile, keyFile, caFile string) Option {
+	return func(a *Aria2) error {
+		if (certFile == "") != (keyFile == "") {
+			return fmt.Errorf("RPC 证书和私钥必须同时设置")
+		}
+		for _, file := range []string{certFile, keyFile} {
+			if file == "" {
+				continue
+			}
+			if _, err := os.Stat(file); err != nil {
+				return fmt.Errorf("无法读取 RPC 证书文件: %w", err)
+			}
+		}
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if caFile != "" {
+			pem, err := os.ReadFile(caFile)
+			if err != nil {
+				return fmt.Errorf("无法读取 CA 证书: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return fmt.Errorf("CA 证书中没有有效的 PEM 证书: %s", caFile)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		tlsConfig.InsecureSkipVerify = a.cfg.rpcInsecure
+		a.cfg.rpcCertFile = certFile
+		a.cfg.rpcKeyFile = keyFile
+		a.cfg.rpcTLS = tlsConfig
+		a.applyRPCTransport()
+		return nil
+	}
+}
+
+// WithRPCInsecureSkipVerify 使用 RPC TLS 时不校验服务端证书，仅用于自签名证书的测试环境
+func WithRPCInsecureSkipVerify(skip bool) Option {
+	return func(a *Aria2) error {
+		a.cfg.rpcInsecure = skip
+		if a.cfg.rpcTLS != nil {
+			a.cfg.rpcTLS.InsecureSkipVerify = skip
+			a.applyRPCTransport()
+		}
+		return nil
+	}
+}
+
+// applyRPCTransport 让 RPC 客户端使用当前的 TLS 配置
+func (a *Aria2) applyRPCTransport() {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = a.cfg.rpcTLS.Clone()
+	a.httpClient.Transport = transport
+}
+
+// rpcScheme 根据是否启用 TLS 返回实际使用的 scheme
+func (a *Aria2) rpcScheme(scheme string) string {
+	if a.cfg.rpcTLS == nil {
+		return scheme
+	}
+	switch scheme {
+	case "http":
+		return "https"
+	case "ws":
+		return "wss"
+	}
+	return scheme
+}
+
+// rpcTLSArgs 返回 RPC TLS 相关的启动参数
+func (a *Aria2) rpcTLSArgs() []string {
+	if a.cfg.rpcCertFile == "" {
+		return nil
+	}
+	return []string{
+		"--rpc-secure=true",
+		"--rpc-certificate=" + a.cfg.rpcCertFile,
+		"--rpc-private-key=" + a.cfg.rpcKeyFile,
+	}
+}
@@ -0,0 +1,63 @@
+package aria2
+
+import (
+	"fmt"
+	"time"
+)
+
+// WithSaveSessionInterval 让 aria2 每隔 d 自动保存一次会话文件，对应 --save-session-interval（按秒向上取整），
+// 需要同时设置 WithSaveSession。默认只在 aria2c 正常退出时保存，异常终止会丢失队列状态
+func WithSaveSessionInterval(d time.Duration) Option {
+	return func(a *Aria2) error {
+		if d <= 0 {
+			return fmt.Errorf("会话保存间隔必须大于0: %v", d)
+		}
+		a.cfg.saveSessionEvery = d
+		return nil
+	}
+}
+
+// WithSessionFlushInterval 由本包每隔 d 调用一次 SaveSession，默认不启用
+// 与 WithSaveSessionInterval 相互独立，用于自动保存不可靠的 aria2 版本；
+// 通过 Attach 连接的服务也会定时保存（服务需要配置了 --save-session）
+func WithSessionFlushInterval(d time.Duration) Option {
+	return func(a *Aria2) error {
+		if d <= 0 {
+			return fmt.Errorf("会话保存间隔必须大于0: %v", d)
+		}
+		a.cfg.flushSessionEvery = d
+		return nil
+	}
+}
+
+// saveSessionArgs 返回会话自动保存相关的启动参数
+func (a *Aria2) saveSessionArgs() []string {
+	if a.cfg.saveSessionEvery <= 0 {
+		return nil
+	}
+	return []string{"--save-session-interval=" + retrySeconds(a.cfg.saveSessionEvery)}
+}
+
+// flushSession 定时保存会话，直到服务停止
+func (a *Aria2) flushSession() {
+	interval := a.cfg.flushSessionEvery
+	if interval <= 0 {
+		return
+	}
+	ticker := a.clock().NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			if !a.IsRunning() {
+				return
+			}
+			if err := a.SaveSession(); err != nil {
+				a.logf("定时保存会话失败: %v", err)
+			}
+		case <-a.ctx.Done():
+			return
+		}
+	}
+}
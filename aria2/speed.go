@@ -0,0 +1,44 @@
+package aria2
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// SpeedProfile 一组全局限速配置
+type SpeedProfile struct {
+	MaxOverallDownloadLimit int64 // 全局最大下载速度（字节/秒），0 表示不限制
+	MaxOverallUploadLimit   int64 // 全局最大上传速度（字节/秒），0 表示不限制
+	MaxConcurrentDownloads  int   // 最大同时下载任务数，必须大于 0
+}
+
+// ApplySpeedProfile 通过一次 changeGlobalOption 调用应用限速配置
+// aria2 先检查全部选项再统一应用，任何一项无效时整个配置都不会生效，不会停留在只改了一部分的状态
+func (a *Aria2) ApplySpeedProfile(profile SpeedProfile) error {
+	if profile.MaxOverallDownloadLimit < 0 || profile.MaxOverallUploadLimit < 0 {
+		return fmt.Errorf("速度限制不能为负数")
+	}
+	if profile.MaxConcurrentDownloads <= 0 {
+		return fmt.Errorf("最大同时下载任务数必须大于0: %d", profile.MaxConcurrentDownloads)
+	}
+
+	// 设置了连接总数上限时，同时下载任务数不能超过上限
+	if n := a.cfg.maxTotalConnections; n > 0 && profile.MaxConcurrentDownloads > n {
+		profile.MaxConcurrentDownloads = n
+	}
+
+	options := map[string]string{
+		"max-overall-download-limit": strconv.FormatInt(profile.MaxOverallDownloadLimit, 10),
+		"max-overall-upload-limit":   strconv.FormatInt(profile.MaxOverallUploadLimit, 10),
+		"max-concurrent-downloads":   strconv.Itoa(profile.MaxConcurrentDownloads),
+	}
+	if _, err := a.Call("aria2.changeGlobalOption", []interface{}{options}); err != nil {
+		return fmt.Errorf("应用限速配置失败: %w", err)
+	}
+
+	// 之后添加的任务按新的同时下载任务数分配连接数
+	a.mu.Lock()
+	a.concurrent = profile.MaxConcurrentDownloads
+	a.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,62 @@
+package aria2
+
+import "fmt"
+
+// Status 任务状态
+type Status int
+
+const (
+	// StatusUnknown 未知状态（aria2 新增的状态或尚未查询到状态）
+	StatusUnknown Status = iota
+	// StatusActive 正在下载或做种
+	StatusActive
+	// StatusWaiting 在队列中等待
+	StatusWaiting
+	// StatusPaused 已暂停
+	StatusPaused
+	// StatusError 出错停止
+	StatusError
+	// StatusComplete 下载完成
+	StatusComplete
+	// StatusRemoved 被用户删除
+	StatusRemoved
+)
+
+// statusNames aria2 返回的状态字符串
+var statusNames = map[Status]string{
+	StatusActive:   "active",
+	StatusWaiting:  "waiting",
+	StatusPaused:   "paused",
+	StatusError:    "error",
+	StatusComplete: "complete",
+	StatusRemoved:  "removed",
+}
+
+// ParseStatus 解析 aria2 返回的状态字符串，无法识别时返回 StatusUnknown 和错误
+func ParseStatus(s string) (Status, error) {
+	for status, name := range statusNames {
+		if name == s {
+			return status, nil
+		}
+	}
+	return StatusUnknown, fmt.Errorf("未知的任务状态: %q", s)
+}
+
+// String 返回 aria2 使用的状态字符串，StatusUnknown 返回 "unknown"
+func (s Status) String() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Done 任务是否已经结束（完成、出错或被删除）
+func (s Status) Done() bool {
+	return s == StatusComplete || s == StatusError || s == StatusRemoved
+}
+
+// State 返回类型化的任务状态，无法识别的状态返回 StatusUnknown，原始字符串仍保留在 Status 字段中
+func (s *DownloadStatus) State() Status {
+	status, _ := ParseStatus(s.Status)
+	return status
+}
@@ -0,0 +1,106 @@
+package aria2
+
+import (
+	"encoding/hex"
+	"math"
+	"strconv"
+)
+
+// CompletedPieces 将十六进制的 bitfield 解码为每个分片的完成状态
+// 返回切片长度等于 numPieces，第 i 个元素表示第 i 个分片是否已完成。
+// aria2 的 bitfield 以最高位表示第一个分片，numPieces 不是 8 的倍数时末尾的填充位会被忽略。
+// 分片信息未知或 bitfield 格式错误时返回 nil。
+func (s *DownloadStatus) CompletedPieces() []bool {
+	numPieces, err := strconv.Atoi(s.NumPieces)
+	if err != nil || numPieces <= 0 {
+		return nil
+	}
+	data, err := hex.DecodeString(s.Bitfield)
+	if err != nil || len(data)*8 < numPieces {
+		return nil
+	}
+
+	pieces := make([]bool, numPieces)
+	for i := range pieces {
+		pieces[i] = data[i/8]&(0x80>>(i%8)) != 0
+	}
+	return pieces
+}
+
+// parseLength 解析 aria2 返回的字节数字符串，格式错误时返回 0
+// 无法解析的值由 warnMalformed 在处理任务状态时输出警告
+func parseLength(s string) int64 {
+	n, _ := parseCount(s)
+	return n
+}
+
+// parseCount 解析 aria2 返回的非负整数字符串
+// 空字符串、非数字、负数以及超出 int64 范围的值都返回 0 和 false，不会 panic
+func parseCount(s string) (int64, bool) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// maxMalformedValues 最多记录的无法解析的值，超过后不再输出警告，避免异常数据占用过多内存
+const maxMalformedValues = 100
+
+// warnMalformed 检查任务状态中的数值，对每个非空但无法解析的值只输出一次警告
+func (a *Aria2) warnMalformed(status *DownloadStatus) {
+	for _, s := range []string{status.TotalLength, status.CompletedLength, status.DownloadSpeed, status.Connections} {
+		if _, ok := parseCount(s); ok || s == "" {
+			continue
+		}
+		a.malformedMu.Lock()
+		warn := !a.malformed[s] && len(a.malformed) < maxMalformedValues
+		if warn {
+			if a.malformed == nil {
+				a.malformed = make(map[string]bool)
+			}
+			a.malformed[s] = true
+		}
+		full := len(a.malformed) == maxMalformedValues
+		a.malformedMu.Unlock()
+
+		if warn {
+			a.logf("无法解析 aria2 返回的数值 %q，按 0 处理", s)
+			if full {
+				a.logf("无法解析的数值过多，之后不再输出警告")
+			}
+		}
+	}
+}
+
+// TotalBytes 返回文件总大小，大小未知或格式错误时返回 0 和 false
+func (s *DownloadStatus) TotalBytes() (int64, bool) {
+	return parseCount(s.TotalLength)
+}
+
+// CompletedBytes 返回已完成大小，格式错误时返回 0 和 false
+func (s *DownloadStatus) CompletedBytes() (int64, bool) {
+	return parseCount(s.CompletedLength)
+}
+
+// Speed 返回下载速度（字节/秒），格式错误时返回 0 和 false
+func (s *DownloadStatus) Speed() (int64, bool) {
+	return parseCount(s.DownloadSpeed)
+}
+
+// NumConnections 返回当前连接数，格式错误时返回 0 和 false
+func (s *DownloadStatus) NumConnections() (int, bool) {
+	n, ok := parseCount(s.Connections)
+	if !ok || n > math.MaxInt32 {
+		return 0, false
+	}
+	return int(n), true
+}
+
+// path 返回第一个文件的路径，文件信息未知时返回空字符串
+func (s *DownloadStatus) path() string {
+	if len(s.Files) == 0 {
+		return ""
+	}
+	return s.Files[0].Path
+}
@@ -0,0 +1,66 @@
+package aria2
+
+import (
+	"bytes"
+	"log"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   int64
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"abc", 0, false},
+		{"12abc", 0, false},
+		{"-1", 0, false},
+		{"0", 0, true},
+		{"1024", 1024, true},
+		{"9223372036854775807", 9223372036854775807, true},
+		{"9223372036854775808", 0, false},
+	}
+	for _, tt := range tests {
+		status := &DownloadStatus{TotalLength: tt.in, CompletedLength: tt.in, DownloadSpeed: tt.in}
+		for name, parse := range map[string]func() (int64, bool){
+			"parseCount":     func() (int64, bool) { return parseCount(tt.in) },
+			"TotalBytes":     status.TotalBytes,
+			"CompletedBytes": status.CompletedBytes,
+			"Speed":          status.Speed,
+		} {
+			if got, ok := parse(); got != tt.want || ok != tt.wantOK {
+				t.Errorf("%s(%q) = %d, %v，期望 %d, %v", name, tt.in, got, ok, tt.want, tt.wantOK)
+			}
+		}
+	}
+}
+
+func TestWarnMalformedUsesInstanceLoggerOnce(t *testing.T) {
+	var buf bytes.Buffer
+	a := newInstance(t, WithLogger(log.New(&buf, "", 0)))
+
+	status := &DownloadStatus{TotalLength: "junk", CompletedLength: "0", DownloadSpeed: ""}
+	a.warnMalformed(status)
+	a.warnMalformed(status)
+	if n := strings.Count(buf.String(), `"junk"`); n != 1 {
+		t.Fatalf("警告输出了 %d 次，期望 1 次:\n%s", n, buf.String())
+	}
+}
+
+func TestWarnMalformedIsCapped(t *testing.T) {
+	var buf bytes.Buffer
+	a := newInstance(t, WithLogger(log.New(&buf, "", 0)))
+
+	for i := 0; i < maxMalformedValues*2; i++ {
+		a.warnMalformed(&DownloadStatus{TotalLength: "x" + strconv.Itoa(i)})
+	}
+	if len(a.malformed) != maxMalformedValues {
+		t.Fatalf("记录了 %d 个值，期望最多 %d 个", len(a.malformed), maxMalformedValues)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != maxMalformedValues+1 {
+		t.Fatalf("输出了 %d 行日志，期望 %d 行", n, maxMalformedValues+1)
+	}
+}
@@ -0,0 +1,27 @@
+package aria2
+
+// StatusChangeCallback 任务状态变化回调函数类型
+// 首次查询到任务状态时 oldStatus 为 StatusUnknown，aria2 返回无法识别的状态时 newStatus 为 StatusUnknown
+type StatusChangeCallback func(gid string, oldStatus, newStatus Status)
+
+// WithOnStatusChange 设置任务状态变化回调，只在状态（active、paused、complete 等）改变时调用，
+// 不会像下载回调那样每次查询都调用，也不受 WithCallbackThrottle 限制
+func WithOnStatusChange(callback StatusChangeCallback) Option {
+	return func(a *Aria2) error {
+		a.cfg.onStatusChange = callback
+		return nil
+	}
+}
+
+// notifyStatusChange 状态与上次查询不同时调用状态变化回调
+func (a *Aria2) notifyStatusChange(w *watch, status *DownloadStatus) {
+	if status.Status == w.lastStatus {
+		return
+	}
+	old := w.lastStatus
+	w.lastStatus = status.Status
+	if a.cfg.onStatusChange != nil {
+		oldStatus, _ := ParseStatus(old)
+		a.cfg.onStatusChange(w.gid, oldStatus, status.State())
+	}
+}
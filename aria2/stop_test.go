@@ -0,0 +1,65 @@
+package aria2
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// TestHelperProcess 不是真正的测试，作为 helperProcess 启动的子进程一直运行直到被结束
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_ARIA2_HELPER_PROCESS") != "1" {
+		return
+	}
+	time.Sleep(time.Minute)
+	os.Exit(0)
+}
+
+// helperProcess 启动代替 aria2c 的子进程
+func helperProcess(t *testing.T) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+	cmd.Env = append(os.Environ(), "GO_ARIA2_HELPER_PROCESS=1")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	return cmd
+}
+
+func TestStopTwice(t *testing.T) {
+	a := newInstance(t)
+	a.cmd = helperProcess(t)
+	a.running = true
+
+	if err := a.Stop(); err != nil {
+		t.Fatalf("第一次 Stop 返回错误: %v", err)
+	}
+	if err := a.Stop(); err != nil {
+		t.Fatalf("第二次 Stop 返回错误: %v", err)
+	}
+}
+
+func TestStopAfterProcessExited(t *testing.T) {
+	a := newInstance(t)
+	cmd := helperProcess(t)
+	a.cmd = cmd
+	a.running = true
+	exited := make(chan struct{})
+	go a.monitor(cmd, "", exited)
+
+	// 进程在 Stop 之前自行退出，monitor 已经回收进程
+	cmd.Process.Kill()
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("进程退出后 monitor 没有结束")
+	}
+	if err := a.Stop(); err != nil {
+		t.Fatalf("进程退出后 Stop 返回错误: %v", err)
+	}
+}
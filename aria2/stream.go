@@ -0,0 +1,124 @@
+package aria2
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// DownloadToWriter 下载文件并在下载过程中将内容写入 w
+// aria2 只能写入磁盘，因此先下载到临时目录，按顺序写出已完成的分片，
+// 完成后写出剩余内容并删除临时文件。为保证数据按顺序到达，该任务只使用单个连接下载。
+func (a *Aria2) DownloadToWriter(url string, w io.Writer, callback DownloadCallback) error {
+	if err := validateURI(url); err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp("", "aria2-stream-*")
+	if err != nil {
+		return fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	gid, err := a.addUri([]string{url}, map[string]interface{}{
+		"dir":                       tmpDir,
+		"split":                     "1",
+		"max-connection-per-server": "1",
+		"stream-piece-selector":     "inorder",
+	})
+	if err != nil {
+		return err
+	}
+
+	s := &streamWriter{w: w}
+	defer s.close()
+
+	ticker := a.clock().NewTicker(a.pollInterval())
+	defer ticker.Stop()
+
+	for {
+		status, err := a.tellStatusRetry(gid)
+		if err != nil {
+			return err
+		}
+		if callback != nil {
+			callback(status)
+		}
+
+		switch status.Status {
+		case "complete":
+			// 写出最后一次轮询之后完成的全部内容
+			return s.flush(status, parseLength(status.TotalLength))
+		case "error":
+			return newDownloadError(status)
+		case "removed":
+			return fmt.Errorf("下载已取消")
+		}
+
+		if err := s.flush(status, contiguousLength(status)); err != nil {
+			a.ForceRemove(gid)
+			return err
+		}
+
+		select {
+		case <-ticker.C():
+		case <-a.ctx.Done():
+			a.ForceRemove(gid)
+			return fmt.Errorf("ctx上下文已取消")
+		}
+	}
+}
+
+// streamWriter 将临时文件中已完成的内容按顺序写出
+type streamWriter struct {
+	w       io.Writer
+	file    *os.File
+	written int64
+}
+
+// flush 将文件中 [written, end) 范围的内容写入 w
+func (s *streamWriter) flush(status *DownloadStatus, end int64) error {
+	if s.file == nil {
+		path := status.path()
+		if path == "" {
+			return nil
+		}
+		file, err := os.Open(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("打开临时文件失败: %w", err)
+		}
+		s.file = file
+	}
+	if end <= s.written {
+		return nil
+	}
+
+	n, err := io.Copy(s.w, io.NewSectionReader(s.file, s.written, end-s.written))
+	s.written += n
+	if err != nil {
+		return fmt.Errorf("写入数据失败: %w", err)
+	}
+	return nil
+}
+
+func (s *streamWriter) close() {
+	if s.file != nil {
+		s.file.Close()
+	}
+}
+
+// contiguousLength 计算从文件开头起连续完成的字节数
+func contiguousLength(status *DownloadStatus) int64 {
+	pieces := status.CompletedPieces()
+	pieceLength := parseLength(status.PieceLength)
+	var n int64
+	for _, done := range pieces {
+		if !done {
+			break
+		}
+		n += pieceLength
+	}
+	return min(n, parseLength(status.TotalLength))
+}
@@ -0,0 +1,55 @@
+package aria2_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dxcweb/go-aria2/aria2"
+	"github.com/dxcweb/go-aria2/aria2/aria2test"
+)
+
+func TestDownloadToWriterPollsOnClock(t *testing.T) {
+	const content = "0123456789abcdef"
+	script := func(uris []string, options map[string]interface{}) []aria2.DownloadStatus {
+		dir, _ := options["dir"].(string)
+		path := filepath.Join(dir, "file.bin")
+		os.WriteFile(path, []byte(content), 0644)
+		files := []aria2.File{{Path: path, Selected: "true"}}
+		return []aria2.DownloadStatus{
+			// 前两块（8 个字节）已完成
+			{Status: "active", TotalLength: "16", CompletedLength: "8", PieceLength: "4", NumPieces: "4", Bitfield: "c0", Files: files},
+			{Status: "complete", TotalLength: "16", CompletedLength: "16", PieceLength: "4", NumPieces: "4", Bitfield: "f0", Files: files},
+		}
+	}
+	clock := aria2test.NewClock(time.Unix(0, 0))
+	a, srv := attachWithClock(t, script, clock, aria2.WithPollInterval(time.Minute))
+
+	var buf bytes.Buffer
+	done := make(chan error, 1)
+	go func() { done <- a.DownloadToWriter("http://example.com/file.bin", &buf, nil) }()
+
+	// 连接后只有带宽采样的 Ticker，第一次查询后等待轮询的 Ticker
+	waitUntil(t, "第一次查询", func() bool {
+		return count(srv.Calls(), "aria2.tellStatus") == 1 && clock.Waiters() == 2
+	})
+	clock.Advance(59 * time.Second)
+	if n := count(srv.Calls(), "aria2.tellStatus"); n != 1 {
+		t.Fatalf("查询间隔未到时调用了 %d 次 tellStatus", n)
+	}
+	clock.Advance(time.Second)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("查询间隔到达后下载没有结束")
+	}
+	if buf.String() != content {
+		t.Fatalf("写出的内容为 %q，期望 %q", buf.String(), content)
+	}
+}
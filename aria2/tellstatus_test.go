@@ -0,0 +1,48 @@
+package aria2_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/dxcweb/go-aria2/aria2"
+)
+
+func TestTellStatusKeysSendsKeys(t *testing.T) {
+	var sent []string
+	opts := newRPCServer(t, func(req rpcRequest) (interface{}, error) {
+		if req.Method != "aria2.tellStatus" {
+			return "OK", nil
+		}
+		if len(req.Params) != 2 {
+			t.Errorf("tellStatus 参数个数为 %d，期望 2（gid 和 keys）", len(req.Params))
+			return nil, &aria2.RPCError{Code: 1, Message: "bad params"}
+		}
+		if err := json.Unmarshal(req.Params[1], &sent); err != nil {
+			t.Errorf("keys 不是字符串数组: %s", req.Params[1])
+		}
+		return map[string]string{"gid": "2089b05ecca3d829", "status": "active"}, nil
+	})
+	a, err := aria2.NewAria2(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{"gid", "status"}
+	status, err := a.TellStatusKeys("2089b05ecca3d829", keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sent, keys) {
+		t.Fatalf("发送的 keys 为 %v，期望 %v", sent, keys)
+	}
+
+	// 未请求的字段保持零值
+	want := aria2.DownloadStatus{GID: "2089b05ecca3d829", Status: "active"}
+	if !reflect.DeepEqual(*status, want) {
+		t.Fatalf("解析结果为 %+v，期望 %+v", *status, want)
+	}
+	if n, ok := status.TotalBytes(); n != 0 || ok {
+		t.Fatalf("未返回的 totalLength 解析为 %d, %v", n, ok)
+	}
+}
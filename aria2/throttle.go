@@ -0,0 +1,52 @@
+package aria2
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultPollInterval 默认的任务状态查询间隔
+const defaultPollInterval = time.Second
+
+// WithPollInterval 设置下载过程中查询任务状态的间隔，默认 1 秒
+// 间隔越短进度越及时，但 RPC 请求越多；可配合 WithCallbackThrottle 限制回调频率
+func WithPollInterval(d time.Duration) Option {
+	return func(a *Aria2) error {
+		if d <= 0 {
+			return fmt.Errorf("查询间隔必须大于0: %v", d)
+		}
+		a.cfg.pollInterval = d
+		return nil
+	}
+}
+
+// WithCallbackThrottle 限制下载回调和进度回调的调用频率，两次调用至少间隔 min
+// 期间的状态更新会被合并（进度回调的 DeltaBytes 会累计），
+// 任务完成、出错或被移除时不受限制，立即回调最终状态
+func WithCallbackThrottle(min time.Duration) Option {
+	return func(a *Aria2) error {
+		if min < 0 {
+			return fmt.Errorf("回调间隔不能为负数: %v", min)
+		}
+		a.cfg.callbackThrottle = min
+		return nil
+	}
+}
+
+// pollInterval 返回任务状态查询间隔
+func (a *Aria2) pollInterval() time.Duration {
+	if a.cfg.pollInterval > 0 {
+		return a.cfg.pollInterval
+	}
+	return defaultPollInterval
+}
+
+// shouldNotify 判断本次状态是否需要回调，终止状态总是回调
+func (w *watch) shouldNotify(status *DownloadStatus, throttle time.Duration, now time.Time) bool {
+	terminal := status.Status == "complete" || status.Status == "error" || status.Status == "removed"
+	if !terminal && throttle > 0 && !w.lastNotify.IsZero() && now.Sub(w.lastNotify) < throttle {
+		return false
+	}
+	w.lastNotify = now
+	return true
+}
@@ -0,0 +1,52 @@
+package aria2
+
+import "time"
+
+// Timing 单个下载任务的耗时信息，零值表示对应的时刻没有发生
+// 时间由本地轮询得到，精度受查询间隔（默认 1 秒）限制；
+// 各时刻包含单调时钟读数，Sub 计算的间隔不受系统时间调整影响
+type Timing struct {
+	QueuedAt    time.Time // 提交任务的时间
+	StartedAt   time.Time // 首次查询到任务进入 active 状态的时间
+	FirstByteAt time.Time // 首次查询到已下载字节数大于 0 的时间
+	CompletedAt time.Time // 查询到任务结束（完成、出错或被移除）的时间
+}
+
+// TimeToFirstByte 返回从提交任务到收到第一个字节的时间，尚未收到时返回 0
+func (t Timing) TimeToFirstByte() time.Duration {
+	if t.QueuedAt.IsZero() || t.FirstByteAt.IsZero() {
+		return 0
+	}
+	return t.FirstByteAt.Sub(t.QueuedAt)
+}
+
+// Duration 返回从提交任务到任务结束的总时间，尚未结束时返回 0
+func (t Timing) Duration() time.Duration {
+	if t.QueuedAt.IsZero() || t.CompletedAt.IsZero() {
+		return 0
+	}
+	return t.CompletedAt.Sub(t.QueuedAt)
+}
+
+// observe 根据最新状态记录各个时刻
+func (t *Timing) observe(status *DownloadStatus, now time.Time) {
+	if t.QueuedAt.IsZero() {
+		t.QueuedAt = now
+	}
+	if t.StartedAt.IsZero() && status.Status == "active" {
+		t.StartedAt = now
+	}
+	if t.FirstByteAt.IsZero() && parseLength(status.CompletedLength) > 0 {
+		t.FirstByteAt = now
+		// 任务在两次查询之间开始并收到数据时，开始时间不晚于收到第一个字节的时间
+		if t.StartedAt.IsZero() {
+			t.StartedAt = now
+		}
+	}
+	switch status.Status {
+	case "complete", "error", "removed":
+		if t.CompletedAt.IsZero() {
+			t.CompletedAt = now
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package aria2
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// FileDetail 任务中单个文件的详细信息（aria2.getFiles 的返回值）
+type FileDetail struct {
+	Index           string `json:"index"`           // 文件序号，从 1 开始
+	Path            string `json:"path"`            // 文件路径
+	Length          string `json:"length"`          // 文件大小
+	CompletedLength string `json:"completedLength"` // 已完成大小
+	Selected        string `json:"selected"`        // 是否选择下载："true" 或 "false"
+	URIs            []URI  `json:"uris"`            // 文件的下载地址
+}
+
+// AddTorrent 添加 BT 种子下载任务，data 为 .torrent 文件内容
+func (a *Aria2) AddTorrent(data []byte, dir string) (string, error) {
+	dir, err := a.ResolveDir(dir)
+	if err != nil {
+		return "", err
+	}
+	return a.addTorrent(data, map[string]interface{}{"dir": dir})
+}
+
+// AddMagnet 添加磁力链接下载任务，aria2 先从 DHT 或 tracker 获取种子信息再开始下载
+func (a *Aria2) AddMagnet(magnet, dir string) (string, error) {
+	u, err := url.Parse(magnet)
+	if err != nil || !strings.EqualFold(u.Scheme, "magnet") {
+		return "", fmt.Errorf("无效的磁力链接: %q", magnet)
+	}
+	// v1 种子为 urn:btih:，v2 种子为 urn:btmh:
+	if xt := u.Query().Get("xt"); !strings.HasPrefix(xt, "urn:btih:") && !strings.HasPrefix(xt, "urn:btmh:") {
+		return "", fmt.Errorf("无效的磁力链接: %q", magnet)
+	}
+	dir, err = a.ResolveDir(dir)
+	if err != nil {
+		return "", err
+	}
+	return a.addUri([]string{magnet}, map[string]interface{}{"dir": dir})
+}
+
+// addTorrent 调用 aria2.addTorrent 添加种子任务并返回 GID
+func (a *Aria2) addTorrent(data []byte, options map[string]interface{}) (string, error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("种子内容不能为空")
+	}
+	if err := a.checkAccepting(); err != nil {
+		return "", err
+	}
+	result, err := a.Call("aria2.addTorrent", []interface{}{
+		base64.StdEncoding.EncodeToString(data),
+		[]string{}, // 种子中的 Web-seed 地址
+		options,
+	})
+	if err != nil {
+		return "", err
+	}
+	var gid string
+	if err := json.Unmarshal(result, &gid); err != nil {
+		return "", fmt.Errorf("解析GID失败: %w", err)
+	}
+	return gid, nil
+}
+
+// GetFiles 获取任务中的文件列表
+func (a *Aria2) GetFiles(gid string) ([]FileDetail, error) {
+	result, err := a.Call("aria2.getFiles", []interface{}{gid})
+	if err != nil {
+		return nil, err
+	}
+	var files []FileDetail
+	if err := json.Unmarshal(result, &files); err != nil {
+		return nil, fmt.Errorf("解析文件列表失败: %w", err)
+	}
+	return files, nil
+}
+
+// TorrentSizes 返回任务中选择下载的文件大小之和与全部文件大小之和，用于显示“共 40 GB，下载其中 3.2 GB”
+// 根据 GetFiles 的文件列表计算，也适用于非 BT 任务（此时两者相同）；
+// 磁力链接在获取到种子信息之前文件大小未知，两者都为 0
+func (a *Aria2) TorrentSizes(gid string) (selected, total int64, err error) {
+	files, err := a.GetFiles(gid)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, file := range files {
+		length := parseLength(file.Length)
+		total += length
+		if file.Selected == "true" {
+			selected += length
+		}
+	}
+	return selected, total, nil
+}
+
+// AddTorrentFiltered 添加种子任务，只下载 want 返回 true 的文件
+// 任务先以暂停状态添加，根据文件列表设置 select-file 后再开始下载；
+// 没有文件匹配时删除任务并返回错误，不会开始下载；want 不能为 nil
+func (a *Aria2) AddTorrentFiltered(data []byte, dir string, want func(FileDetail) bool) (string, error) {
+	if want == nil {
+		return "", fmt.Errorf("文件筛选函数不能为 nil")
+	}
+	dir, err := a.ResolveDir(dir)
+	if err != nil {
+		return "", err
+	}
+	gid, err := a.addTorrent(data, map[string]interface{}{
+		"dir":   dir,
+		"pause": "true",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// 出错时删除暂停中的任务，避免残留
+	discard := func(err error) (string, error) {
+		a.ForceRemove(gid)
+		a.RemoveDownloadResult(gid)
+		return "", err
+	}
+
+	files, err := a.GetFiles(gid)
+	if err != nil {
+		return discard(err)
+	}
+	var selected []string
+	for _, file := range files {
+		if want(file) {
+			selected = append(selected, file.Index)
+		}
+	}
+	if len(selected) == 0 {
+		return discard(fmt.Errorf("种子中没有符合条件的文件（共 %d 个文件）", len(files)))
+	}
+
+	if err := a.ChangeOption(gid, map[string]string{"select-file": strings.Join(selected, ",")}); err != nil {
+		return discard(fmt.Errorf("设置下载文件失败: %w", err))
+	}
+	if err := a.Unpause(gid); err != nil {
+		return gid, fmt.Errorf("开始下载失败: %w", err)
+	}
+	return gid, nil
+}
+
+// MatchExtensions 返回按扩展名筛选文件的函数（不区分大小写），用于 AddTorrentFiltered
+// 例如 MatchExtensions(".mkv", ".mp4")
+func MatchExtensions(exts ...string) func(FileDetail) bool {
+	set := make(map[string]bool, len(exts))
+	for _, ext := range exts {
+		ext = strings.ToLower(ext)
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		set[ext] = true
+	}
+	return func(file FileDetail) bool {
+		return set[strings.ToLower(filepath.Ext(file.Path))]
+	}
+}
@@ -0,0 +1,22 @@
+package aria2_test
+
+import (
+	"testing"
+
+	"github.com/dxcweb/go-aria2/aria2"
+)
+
+func TestAddTorrentFilteredRejectsNilFilter(t *testing.T) {
+	opts := newRPCServer(t, func(req rpcRequest) (interface{}, error) {
+		t.Errorf("want 为 nil 时不应调用 %s", req.Method)
+		return "OK", nil
+	})
+	a, err := aria2.NewAria2(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := a.AddTorrentFiltered([]byte("d4:infod4:name1:aee"), t.TempDir(), nil); err == nil {
+		t.Fatal("want 为 nil 时应返回错误")
+	}
+}
@@ -0,0 +1,56 @@
+package aria2
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"testing"
+)
+
+// timeoutError 超时的 net.Error
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsTransientError(t *testing.T) {
+	// 监听后立即关闭，再连接会被拒绝
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	_, refused := net.Dial("tcp", addr)
+	if refused == nil {
+		t.Skip("端口已被其他进程占用")
+	}
+
+	wrap := func(err error) error {
+		return &url.Error{Op: "Post", URL: "http://localhost:6800/jsonrpc", Err: err}
+	}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "超时", err: wrap(timeoutError{}), want: true},
+		{name: "连接被拒绝", err: wrap(refused), want: true},
+		{name: "连接中断", err: wrap(io.EOF), want: true},
+		{name: "响应不完整", err: fmt.Errorf("读取响应失败: %w", io.ErrUnexpectedEOF), want: true},
+		{name: "证书校验失败", err: wrap(&x509.UnknownAuthorityError{}), want: false},
+		{name: "不支持的协议", err: wrap(errors.New(`unsupported protocol scheme "ftp"`)), want: false},
+		{name: "RPC 错误", err: &RPCError{Code: 1, Message: "GID 2089b05ecca3d829 is not found"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientError(tt.err); got != tt.want {
+				t.Fatalf("isTransientError(%v) = %v，期望 %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
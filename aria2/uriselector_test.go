@@ -0,0 +1,29 @@
+package aria2
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestURISelectorArgs(t *testing.T) {
+	for _, mode := range []string{"feedback", "inorder", "adaptive"} {
+		args := newInstance(t, WithURISelector(mode)).buildArgs()
+		if !hasArg(args, "--uri-selector="+mode) {
+			t.Errorf("参数中缺少 --uri-selector=%s: %v", mode, args)
+		}
+	}
+
+	for _, arg := range newInstance(t).buildArgs() {
+		if strings.HasPrefix(arg, "--uri-selector") {
+			t.Errorf("默认不应设置 %s", arg)
+		}
+	}
+}
+
+func TestWithURISelectorRejectsInvalid(t *testing.T) {
+	for _, mode := range []string{"", "random", "InOrder"} {
+		if _, err := NewAria2(WithURISelector(mode)); err == nil {
+			t.Errorf("WithURISelector(%q) 应返回错误", mode)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package aria2
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// newHash 根据算法名创建哈希函数，算法名与 aria2 的 --checksum 一致（sha-1、sha-256、md5）
+func newHash(algo string) (hash.Hash, error) {
+	switch strings.ToLower(algo) {
+	case "sha-1", "sha1":
+		return sha1.New(), nil
+	case "sha-256", "sha256":
+		return sha256.New(), nil
+	case "md5":
+		return md5.New(), nil
+	default:
+		return nil, fmt.Errorf("不支持的哈希算法: %s", algo)
+	}
+}
+
+// VerifyFile 校验文件哈希是否与 expectedHash（十六进制，不区分大小写）一致
+// 以流式方式读取文件，大文件不会一次性载入内存
+func VerifyFile(path string, expectedHash, algo string) (bool, error) {
+	h, err := newHash(algo)
+	if err != nil {
+		return false, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(h, file); err != nil {
+		return false, fmt.Errorf("读取文件失败: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)) == strings.ToLower(strings.TrimSpace(expectedHash)), nil
+}
@@ -0,0 +1,46 @@
+package aria2
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseVersionOutput(t *testing.T) {
+	tests := map[string]string{
+		"aria2 version 1.37.0\nCopyright (C) 2006, 2019 Tatsuhiro Tsujikawa\n": "1.37.0",
+		"aria2 version 1.18.10\r\n": "1.18.10",
+		"":                          "",
+		"aria2c: command not found": "",
+	}
+	for output, want := range tests {
+		if got := parseVersionOutput(output); got != want {
+			t.Errorf("parseVersionOutput(%q) = %q，期望 %q", output, got, want)
+		}
+	}
+}
+
+func TestSupportedArgs(t *testing.T) {
+	args := []string{
+		"--rpc-listen-port=6800",
+		"--optimize-concurrent-downloads=true",
+		"--content-disposition-default-utf8=true",
+		"--split=64",
+	}
+
+	a := newInstance(t)
+	if got := a.supportedArgs(args, ""); !slices.Equal(got, args) {
+		t.Errorf("无法获取版本时参数为 %v，期望原样保留", got)
+	}
+	if got := a.supportedArgs(args, "1.37.0"); !slices.Equal(got, args) {
+		t.Errorf("新版本的参数为 %v，期望原样保留", got)
+	}
+
+	got := a.supportedArgs(args, "1.30.0")
+	want := []string{"--rpc-listen-port=6800", "--optimize-concurrent-downloads=true", "--split=64"}
+	if !slices.Equal(got, want) {
+		t.Errorf("1.30.0 的参数为 %v，期望 %v", got, want)
+	}
+	if warnings := a.CompatibilityWarnings(); len(warnings) != 1 {
+		t.Errorf("兼容性警告为 %v，期望只有 content-disposition-default-utf8 一条", warnings)
+	}
+}
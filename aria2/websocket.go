@@ -0,0 +1,246 @@
+package aria2
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// WebSocket 帧类型（RFC 6455）
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// wsGUID 计算 Sec-WebSocket-Accept 使用的固定 GUID
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsMaxMessageSize 单条消息的最大长度，防止异常数据占用过多内存
+const wsMaxMessageSize = 16 << 20
+
+// errWebSocketClosed 对端关闭了 WebSocket 连接
+var errWebSocketClosed = errors.New("WebSocket 连接已关闭")
+
+// wsConn 最小化的 WebSocket 客户端连接，只支持 aria2 通知所需的功能
+type wsConn struct {
+	conn    net.Conn
+	br      *bufio.Reader
+	writeMu sync.Mutex
+}
+
+// dialWebSocket 连接 WebSocket 服务并完成握手，wss 地址使用 tlsConfig 建立 TLS 连接
+func dialWebSocket(ctx context.Context, rawURL string, tlsConfig *tls.Config) (*wsConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 WebSocket 地址: %w", err)
+	}
+	if u.Scheme != "ws" && u.Scheme != "wss" {
+		return nil, fmt.Errorf("不支持的 WebSocket 协议: %s", u.Scheme)
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		return nil, fmt.Errorf("连接 WebSocket 失败: %w", err)
+	}
+	if u.Scheme == "wss" {
+		cfg := tlsConfig.Clone()
+		if cfg == nil {
+			cfg = &tls.Config{}
+		}
+		if cfg.ServerName == "" {
+			cfg.ServerName = u.Hostname()
+		}
+		tlsConn := tls.Client(conn, cfg)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("WebSocket TLS 握手失败: %w", err)
+		}
+		conn = tlsConn
+	}
+	// 握手期间跟随 ctx 取消
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+
+	req := &http.Request{
+		Method: "GET",
+		URL:    u,
+		Host:   u.Host,
+		Header: http.Header{
+			"Upgrade":               {"websocket"},
+			"Connection":            {"Upgrade"},
+			"Sec-WebSocket-Key":     {key},
+			"Sec-WebSocket-Version": {"13"},
+		},
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("发送 WebSocket 握手失败: %w", err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("读取 WebSocket 握手响应失败: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols ||
+		!strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") ||
+		resp.Header.Get("Sec-WebSocket-Accept") != wsAcceptKey(key) {
+		conn.Close()
+		return nil, fmt.Errorf("WebSocket 握手失败: %s", resp.Status)
+	}
+	if ctx.Err() != nil {
+		conn.Close()
+		return nil, ctx.Err()
+	}
+	return &wsConn{conn: conn, br: br}, nil
+}
+
+// wsAcceptKey 根据客户端 key 计算服务端应返回的 Sec-WebSocket-Accept
+func wsAcceptKey(key string) string {
+	h := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// ReadMessage 读取一条完整的文本或二进制消息，自动回复 ping
+func (c *wsConn) ReadMessage() ([]byte, error) {
+	var message []byte
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			c.writeFrame(wsOpClose, nil)
+			return nil, errWebSocketClosed
+		case wsOpText, wsOpBinary, wsOpContinuation:
+			message = append(message, payload...)
+			if len(message) > wsMaxMessageSize {
+				return nil, fmt.Errorf("WebSocket 消息过大")
+			}
+			if fin {
+				return message, nil
+			}
+		default:
+			return nil, fmt.Errorf("未知的 WebSocket 帧类型: %d", opcode)
+		}
+	}
+}
+
+// readFrame 读取一个帧
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(c.br, header[:]); err != nil {
+		return
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > wsMaxMessageSize {
+		err = fmt.Errorf("WebSocket 帧过大: %d", length)
+		return
+	}
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return
+}
+
+// WriteMessage 发送一条文本消息
+func (c *wsConn) WriteMessage(data []byte) error {
+	return c.writeFrame(wsOpText, data)
+}
+
+// writeFrame 发送一个帧，客户端发送的帧必须加掩码
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	frame := []byte{0x80 | opcode}
+	n := len(payload)
+	switch {
+	case n < 126:
+		frame = append(frame, 0x80|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// Close 关闭连接
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"log"
-	"strconv"
 	"time"
 
 	"github.com/dxcweb/go-aria2/aria2"
@@ -24,23 +23,14 @@ func main() {
 		elapsed := currentTime.Sub(startTime)
 
 		fmt.Printf("下载状态: %s (已用时: %v)\n", status.Status, elapsed)
-		if status.TotalLength != "" {
-			total, _ := strconv.ParseInt(status.TotalLength, 10, 64)
-			completed, _ := strconv.ParseInt(status.CompletedLength, 10, 64)
-			if total > 0 {
-				progress := float64(completed) / float64(total) * 100
-				fmt.Printf("进度: %.2f%% (%s/%s)\n", progress, status.CompletedLength, status.TotalLength)
-			}
+		total, totalOK := status.TotalBytes()
+		completed, _ := status.CompletedBytes()
+		if totalOK && total > 0 {
+			progress := float64(completed) / float64(total) * 100
+			fmt.Printf("进度: %.2f%% (%s/%s)\n", progress, aria2.FormatBytes(completed), aria2.FormatBytes(total))
 		}
-		if status.DownloadSpeed != "" {
-			// 将下载速度从字节转换为MB/s
-			speedBytes, err := strconv.ParseInt(status.DownloadSpeed, 10, 64)
-			if err == nil {
-				speedMB := float64(speedBytes) / (1024 * 1024) // 转换为MB
-				fmt.Printf("下载速度: %.2f MB/s\n", speedMB)
-			} else {
-				fmt.Printf("下载速度: %s/s\n", status.DownloadSpeed)
-			}
+		if speed, ok := status.Speed(); ok {
+			fmt.Printf("下载速度: %s\n", aria2.FormatSpeed(speed))
 		}
 		if status.ErrorMessage != "" {
 			fmt.Printf("错误信息: %s\n", status.ErrorMessage)